@@ -1,11 +1,8 @@
 module golang_course
 
-go 1.22
+go 1.24.0
 
-require (
-	github.com/stretchr/testify v1.9.0
-	golang.org/x/text v0.18.0
-)
+require github.com/stretchr/testify v1.9.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
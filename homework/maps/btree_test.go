@@ -0,0 +1,1061 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+// cowToken marks the nodes owned by one map. Nodes with a foreign
+// token are shared with a clone and are copied before modification.
+type cowToken struct {
+	_ byte
+}
+
+type btreeNode[K, V any] struct {
+	keys     []K
+	values   []V
+	children []*btreeNode[K, V]
+	size     int
+	cow      *cowToken
+}
+
+func (n *btreeNode[K, V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// BTreeMap keeps keys and values in separate arrays of up to 2*degree-1
+// elements, so a search touches a few cache lines instead of a pointer
+// per key like OrderedMap. The zero value is an empty map of defaultDegree
+// ordered the same way as the zero value of OrderedMap.
+type BTreeMap[K, V any] struct {
+	root    *btreeNode[K, V]
+	degree  int
+	compare func(K, K) int
+	cow     *cowToken
+}
+
+const defaultDegree = 32
+
+func NewBTreeMap[K cmp.Ordered, V any](degree int) BTreeMap[K, V] {
+	return NewBTreeMapFunc[K, V](degree, cmp.Compare[K])
+}
+
+func NewBTreeMapFunc[K, V any](degree int, compare func(K, K) int) BTreeMap[K, V] {
+	if degree < 2 {
+		panic("degree must be at least 2")
+	}
+
+	return BTreeMap[K, V]{degree: degree, compare: compare, cow: new(cowToken)}
+}
+
+// lazyInit prepares the zero value for the first modification.
+func (m *BTreeMap[K, V]) lazyInit() {
+	if m.degree == 0 {
+		m.degree = defaultDegree
+	}
+
+	if m.compare == nil {
+		m.compare = orderedCompare[K]()
+	}
+
+	if m.cow == nil {
+		m.cow = new(cowToken)
+	}
+}
+
+func (m *BTreeMap[K, V]) maxKeys() int {
+	return 2*m.degree - 1
+}
+
+// Clone returns a snapshot in O(1). Both maps share the nodes
+// and copy only the paths they modify afterwards.
+func (m *BTreeMap[K, V]) Clone() BTreeMap[K, V] {
+	clone := *m
+	m.cow = new(cowToken)
+	clone.cow = new(cowToken)
+	return clone
+}
+
+// BulkLoad builds the tree from entries sorted by strictly increasing keys
+// in O(n). It returns false if the map isn't empty or the input isn't sorted.
+func (m *BTreeMap[K, V]) BulkLoad(sorted iter.Seq2[K, V]) bool {
+	if m.root != nil {
+		return false
+	}
+
+	m.lazyInit()
+
+	var keys []K
+	var values []V
+	for key, value := range sorted {
+		if len(keys) > 0 && m.compare(keys[len(keys)-1], key) >= 0 {
+			return false
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	if len(keys) == 0 {
+		return true
+	}
+
+	height := 1
+	for capacity := m.maxKeys(); capacity < len(keys); height++ {
+		capacity = capacity*2*m.degree + m.maxKeys()
+	}
+
+	m.root = m.build(keys, values, height, true)
+	return true
+}
+
+func (m *BTreeMap[K, V]) build(keys []K, values []V, height int, root bool) *btreeNode[K, V] {
+	node := m.newNode()
+	node.size = len(keys)
+	if height == 1 {
+		node.keys = append(node.keys, keys...)
+		node.values = append(node.values, values...)
+		return node
+	}
+
+	childCapacity := m.maxKeys()
+	for range height - 2 {
+		childCapacity = childCapacity*2*m.degree + m.maxKeys()
+	}
+
+	// As few children as fit the keys, but not fewer than a node
+	// must have, so every child gets at least its minimum of keys.
+	childrenNumber := (len(keys) + 1 + childCapacity) / (childCapacity + 1)
+	if root {
+		childrenNumber = max(childrenNumber, 2)
+	} else {
+		childrenNumber = max(childrenNumber, m.degree)
+	}
+
+	inChildren := len(keys) - (childrenNumber - 1)
+	from := 0
+	for child := range childrenNumber {
+		to := from + inChildren/childrenNumber
+		if child < inChildren%childrenNumber {
+			to++
+		}
+
+		node.children = append(node.children, m.build(keys[from:to], values[from:to], height-1, false))
+		if child < childrenNumber-1 {
+			node.keys = append(node.keys, keys[to])
+			node.values = append(node.values, values[to])
+		}
+
+		from = to + 1
+	}
+
+	return node
+}
+
+func (m *BTreeMap[K, V]) Insert(key K, value V) {
+	if m.root == nil {
+		m.lazyInit()
+		m.root = m.newNode()
+		m.root.keys = append(m.root.keys, key)
+		m.root.values = append(m.root.values, value)
+		m.root.size = 1
+		return
+	}
+
+	m.root = m.mutable(m.root)
+	if len(m.root.keys) == m.maxKeys() {
+		root := m.newNode()
+		root.children = append(root.children, m.root)
+		root.size = m.root.size
+		m.splitChild(root, 0)
+		m.root = root
+	}
+
+	m.insertNonFull(m.root, key, value)
+}
+
+func (m *BTreeMap[K, V]) insertNonFull(node *btreeNode[K, V], key K, value V) bool {
+	idx, found := m.search(node, key)
+	if found {
+		node.values[idx] = value
+		return false
+	}
+
+	if node.leaf() {
+		node.keys = slices.Insert(node.keys, idx, key)
+		node.values = slices.Insert(node.values, idx, value)
+		node.size++
+		return true
+	}
+
+	child := m.mutableChild(node, idx)
+	if len(child.keys) == m.maxKeys() {
+		m.splitChild(node, idx)
+
+		result := m.compare(key, node.keys[idx])
+		if result == 0 {
+			node.values[idx] = value
+			return false
+		} else if result > 0 {
+			idx++
+		}
+
+		child = node.children[idx]
+	}
+
+	added := m.insertNonFull(child, key, value)
+	if added {
+		node.size++
+	}
+
+	return added
+}
+
+// splitChild moves the upper half of a full child to a new node
+// and the median to the parent. Both nodes must be mutable.
+func (m *BTreeMap[K, V]) splitChild(parent *btreeNode[K, V], idx int) {
+	child := parent.children[idx]
+	middle := m.degree - 1
+
+	right := m.newNode()
+	right.keys = append(right.keys, child.keys[middle+1:]...)
+	right.values = append(right.values, child.values[middle+1:]...)
+	if !child.leaf() {
+		right.children = append(right.children, child.children[middle+1:]...)
+	}
+
+	key, value := child.keys[middle], child.values[middle]
+	child.keys = truncate(child.keys, middle)
+	child.values = truncate(child.values, middle)
+	if !child.leaf() {
+		child.children = truncate(child.children, middle+1)
+	}
+
+	right.size = countSize(right)
+	child.size = countSize(child)
+
+	parent.keys = slices.Insert(parent.keys, idx, key)
+	parent.values = slices.Insert(parent.values, idx, value)
+	parent.children = slices.Insert(parent.children, idx+1, right)
+}
+
+func (m *BTreeMap[K, V]) Erase(key K) {
+	if m.root == nil {
+		return
+	}
+
+	m.root = m.mutable(m.root)
+	m.remove(m.root, key)
+
+	if len(m.root.keys) == 0 {
+		if m.root.leaf() {
+			m.root = nil
+		} else {
+			m.root = m.root.children[0]
+		}
+	}
+}
+
+// remove expects node to be mutable and, unless it is the root,
+// to have at least degree keys, so a key can be taken from it.
+func (m *BTreeMap[K, V]) remove(node *btreeNode[K, V], key K) bool {
+	idx, found := m.search(node, key)
+	if node.leaf() {
+		if !found {
+			return false
+		}
+
+		node.keys = slices.Delete(node.keys, idx, idx+1)
+		node.values = slices.Delete(node.values, idx, idx+1)
+		node.size--
+		return true
+	}
+
+	if found {
+		if len(node.children[idx].keys) >= m.degree {
+			left := m.mutableChild(node, idx)
+			node.keys[idx], node.values[idx] = m.removeMax(left)
+			node.size--
+			return true
+		}
+
+		if len(node.children[idx+1].keys) >= m.degree {
+			right := m.mutableChild(node, idx+1)
+			node.keys[idx], node.values[idx] = m.removeMin(right)
+			node.size--
+			return true
+		}
+
+		m.mutableChild(node, idx)
+		m.merge(node, idx)
+		m.remove(node.children[idx], key)
+		node.size--
+		return true
+	}
+
+	idx = m.growChild(node, idx)
+	removed := m.remove(node.children[idx], key)
+	if removed {
+		node.size--
+	}
+
+	return removed
+}
+
+func (m *BTreeMap[K, V]) removeMin(node *btreeNode[K, V]) (K, V) {
+	node.size--
+	if node.leaf() {
+		key, value := node.keys[0], node.values[0]
+		node.keys = slices.Delete(node.keys, 0, 1)
+		node.values = slices.Delete(node.values, 0, 1)
+		return key, value
+	}
+
+	idx := m.growChild(node, 0)
+	return m.removeMin(node.children[idx])
+}
+
+func (m *BTreeMap[K, V]) removeMax(node *btreeNode[K, V]) (K, V) {
+	node.size--
+	if node.leaf() {
+		last := len(node.keys) - 1
+		key, value := node.keys[last], node.values[last]
+		node.keys = truncate(node.keys, last)
+		node.values = truncate(node.values, last)
+		return key, value
+	}
+
+	idx := m.growChild(node, len(node.children)-1)
+	return m.removeMax(node.children[idx])
+}
+
+// growChild makes sure the child has at least degree keys by borrowing
+// from a sibling or merging with it. It returns the new index of the
+// child, which becomes mutable.
+func (m *BTreeMap[K, V]) growChild(node *btreeNode[K, V], idx int) int {
+	child := m.mutableChild(node, idx)
+	if len(child.keys) >= m.degree {
+		return idx
+	}
+
+	if idx > 0 && len(node.children[idx-1].keys) >= m.degree {
+		left := m.mutableChild(node, idx-1)
+		last := len(left.keys) - 1
+
+		child.keys = slices.Insert(child.keys, 0, node.keys[idx-1])
+		child.values = slices.Insert(child.values, 0, node.values[idx-1])
+		node.keys[idx-1], node.values[idx-1] = left.keys[last], left.values[last]
+		left.keys = truncate(left.keys, last)
+		left.values = truncate(left.values, last)
+
+		moved := 1
+		if !left.leaf() {
+			grandchild := left.children[last+1]
+			left.children = truncate(left.children, last+1)
+			child.children = slices.Insert(child.children, 0, grandchild)
+			moved += grandchild.size
+		}
+
+		left.size -= moved
+		child.size += moved
+		return idx
+	}
+
+	if idx < len(node.children)-1 && len(node.children[idx+1].keys) >= m.degree {
+		right := m.mutableChild(node, idx+1)
+
+		child.keys = append(child.keys, node.keys[idx])
+		child.values = append(child.values, node.values[idx])
+		node.keys[idx], node.values[idx] = right.keys[0], right.values[0]
+		right.keys = slices.Delete(right.keys, 0, 1)
+		right.values = slices.Delete(right.values, 0, 1)
+
+		moved := 1
+		if !right.leaf() {
+			grandchild := right.children[0]
+			right.children = slices.Delete(right.children, 0, 1)
+			child.children = append(child.children, grandchild)
+			moved += grandchild.size
+		}
+
+		right.size -= moved
+		child.size += moved
+		return idx
+	}
+
+	if idx > 0 {
+		m.mutableChild(node, idx-1)
+		m.merge(node, idx-1)
+		return idx - 1
+	}
+
+	m.merge(node, idx)
+	return idx
+}
+
+// merge joins children idx and idx+1 with the key between them.
+// The left child must be mutable, the right one is only read.
+func (m *BTreeMap[K, V]) merge(node *btreeNode[K, V], idx int) {
+	left, right := node.children[idx], node.children[idx+1]
+
+	left.keys = append(append(left.keys, node.keys[idx]), right.keys...)
+	left.values = append(append(left.values, node.values[idx]), right.values...)
+	left.children = append(left.children, right.children...)
+	left.size += 1 + right.size
+
+	node.keys = slices.Delete(node.keys, idx, idx+1)
+	node.values = slices.Delete(node.values, idx, idx+1)
+	node.children = slices.Delete(node.children, idx+1, idx+2)
+}
+
+func (m *BTreeMap[K, V]) newNode() *btreeNode[K, V] {
+	return &btreeNode[K, V]{
+		keys:   make([]K, 0, m.maxKeys()),
+		values: make([]V, 0, m.maxKeys()),
+		cow:    m.cow,
+	}
+}
+
+func (m *BTreeMap[K, V]) mutable(node *btreeNode[K, V]) *btreeNode[K, V] {
+	if node.cow == m.cow {
+		return node
+	}
+
+	copied := m.newNode()
+	copied.keys = append(copied.keys, node.keys...)
+	copied.values = append(copied.values, node.values...)
+	if !node.leaf() {
+		copied.children = append(make([]*btreeNode[K, V], 0, m.maxKeys()+1), node.children...)
+	}
+
+	copied.size = node.size
+	return copied
+}
+
+func (m *BTreeMap[K, V]) mutableChild(node *btreeNode[K, V], idx int) *btreeNode[K, V] {
+	child := m.mutable(node.children[idx])
+	node.children[idx] = child
+	return child
+}
+
+func (m *BTreeMap[K, V]) search(node *btreeNode[K, V], key K) (int, bool) {
+	return slices.BinarySearchFunc(node.keys, key, m.compare)
+}
+
+func (m *BTreeMap[K, V]) Get(key K) (V, bool) {
+	for node := m.root; node != nil; {
+		idx, found := m.search(node, key)
+		if found {
+			return node.values[idx], true
+		} else if node.leaf() {
+			break
+		}
+
+		node = node.children[idx]
+	}
+
+	var zero V
+	return zero, false
+}
+
+func (m *BTreeMap[K, V]) Contains(key K) bool {
+	_, found := m.Get(key)
+	return found
+}
+
+func (m *BTreeMap[K, V]) Size() int {
+	if m.root == nil {
+		return 0
+	}
+
+	return m.root.size
+}
+
+func (m *BTreeMap[K, V]) ForEach(action func(K, V)) {
+	for key, value := range m.All() {
+		action(key, value)
+	}
+}
+
+func (m *BTreeMap[K, V]) Min() (K, V, bool) {
+	node := m.root
+	if node == nil {
+		return btreeEntry[K, V](nil, 0)
+	}
+
+	for !node.leaf() {
+		node = node.children[0]
+	}
+
+	return btreeEntry(node, 0)
+}
+
+func (m *BTreeMap[K, V]) Max() (K, V, bool) {
+	node := m.root
+	if node == nil {
+		return btreeEntry[K, V](nil, 0)
+	}
+
+	for !node.leaf() {
+		node = node.children[len(node.children)-1]
+	}
+
+	return btreeEntry(node, len(node.keys)-1)
+}
+
+// Floor returns the greatest key less than or equal to key.
+func (m *BTreeMap[K, V]) Floor(key K) (K, V, bool) {
+	var found *btreeNode[K, V]
+	var foundIdx int
+	for node := m.root; node != nil; {
+		idx, exact := m.search(node, key)
+		if exact {
+			return btreeEntry(node, idx)
+		} else if idx > 0 {
+			found, foundIdx = node, idx-1
+		}
+
+		if node.leaf() {
+			break
+		}
+
+		node = node.children[idx]
+	}
+
+	return btreeEntry(found, foundIdx)
+}
+
+// Ceiling returns the least key greater than or equal to key.
+func (m *BTreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var found *btreeNode[K, V]
+	var foundIdx int
+	for node := m.root; node != nil; {
+		idx, exact := m.search(node, key)
+		if exact {
+			return btreeEntry(node, idx)
+		} else if idx < len(node.keys) {
+			found, foundIdx = node, idx
+		}
+
+		if node.leaf() {
+			break
+		}
+
+		node = node.children[idx]
+	}
+
+	return btreeEntry(found, foundIdx)
+}
+
+// Rank returns the number of keys less than key.
+func (m *BTreeMap[K, V]) Rank(key K) int {
+	rank := 0
+	for node := m.root; node != nil; {
+		idx, found := m.search(node, key)
+		rank += idx
+		if node.leaf() {
+			break
+		}
+
+		for _, child := range node.children[:idx] {
+			rank += child.size
+		}
+
+		if found {
+			rank += node.children[idx].size
+			break
+		}
+
+		node = node.children[idx]
+	}
+
+	return rank
+}
+
+// Select returns the entry with the given zero-based rank.
+func (m *BTreeMap[K, V]) Select(rank int) (K, V, bool) {
+	if rank < 0 || rank >= m.Size() {
+		return btreeEntry[K, V](nil, 0)
+	}
+
+	node := m.root
+	for !node.leaf() {
+		idx := 0
+		for ; rank >= node.children[idx].size; idx++ {
+			rank -= node.children[idx].size
+			if rank == 0 {
+				return btreeEntry(node, idx)
+			}
+
+			rank--
+		}
+
+		node = node.children[idx]
+	}
+
+	return btreeEntry(node, rank)
+}
+
+func (m *BTreeMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		btreeAscend(m.root, yield)
+	}
+}
+
+func (m *BTreeMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		btreeDescend(m.root, yield)
+	}
+}
+
+func (m *BTreeMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func (m *BTreeMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Range iterates over keys in [lo, hi) in ascending order.
+func (m *BTreeMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.ascendRange(m.root, lo, hi, yield)
+	}
+}
+
+func (m *BTreeMap[K, V]) ascendRange(node *btreeNode[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	from, _ := m.search(node, lo)
+	for idx := from; idx <= len(node.keys); idx++ {
+		if !node.leaf() && !m.ascendRange(node.children[idx], lo, hi, yield) {
+			return false
+		}
+
+		if idx == len(node.keys) {
+			return true
+		}
+
+		// Keys from here on are out of range, so is everything to the right.
+		if m.compare(node.keys[idx], hi) >= 0 || !yield(node.keys[idx], node.values[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func btreeAscend[K, V any](node *btreeNode[K, V], yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	for idx := range node.keys {
+		if !node.leaf() && !btreeAscend(node.children[idx], yield) {
+			return false
+		}
+
+		if !yield(node.keys[idx], node.values[idx]) {
+			return false
+		}
+	}
+
+	return node.leaf() || btreeAscend(node.children[len(node.keys)], yield)
+}
+
+func btreeDescend[K, V any](node *btreeNode[K, V], yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	for idx := len(node.keys) - 1; idx >= 0; idx-- {
+		if !node.leaf() && !btreeDescend(node.children[idx+1], yield) {
+			return false
+		}
+
+		if !yield(node.keys[idx], node.values[idx]) {
+			return false
+		}
+	}
+
+	return node.leaf() || btreeDescend(node.children[0], yield)
+}
+
+func btreeEntry[K, V any](node *btreeNode[K, V], idx int) (K, V, bool) {
+	if node == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+
+	return node.keys[idx], node.values[idx], true
+}
+
+func countSize[K, V any](node *btreeNode[K, V]) int {
+	size := len(node.keys)
+	for _, child := range node.children {
+		size += child.size
+	}
+
+	return size
+}
+
+// truncate clears the tail, so the removed elements can be collected.
+func truncate[T any](values []T, length int) []T {
+	clear(values[length:])
+	return values[:length]
+}
+
+func TestBTreeMap(t *testing.T) {
+	data := NewBTreeMap[int, int](2)
+	assert.Zero(t, data.Size())
+
+	for _, key := range []int{10, 5, 15, 2, 4, 12, 14} {
+		data.Insert(key, key*10)
+	}
+
+	assert.Equal(t, 7, data.Size())
+	assert.True(t, data.Contains(4))
+	assert.False(t, data.Contains(3))
+	assert.Equal(t, []int{2, 4, 5, 10, 12, 14, 15}, slices.Collect(data.Keys()))
+
+	value, ok := data.Get(12)
+	assert.True(t, ok)
+	assert.Equal(t, 120, value)
+
+	data.Erase(15)
+	data.Erase(14)
+	data.Erase(2)
+	data.Erase(100)
+
+	assert.Equal(t, 4, data.Size())
+	assert.Equal(t, []int{4, 5, 10, 12}, slices.Collect(data.Keys()))
+
+	var keys []int
+	data.ForEach(func(key, _ int) {
+		keys = append(keys, key)
+	})
+	assert.Equal(t, []int{4, 5, 10, 12}, keys)
+
+	for _, key := range []int{4, 5, 10, 12} {
+		data.Erase(key)
+	}
+
+	assert.Zero(t, data.Size())
+	assert.Nil(t, data.root)
+}
+
+func TestBTreeMapQueries(t *testing.T) {
+	data := NewBTreeMap[int, string](2)
+	for _, key := range []int{40, 10, 30, 20, 50, 60, 70, 80} {
+		data.Insert(key, "value")
+	}
+
+	key, _, ok := data.Min()
+	assert.True(t, ok)
+	assert.Equal(t, 10, key)
+	key, _, _ = data.Max()
+	assert.Equal(t, 80, key)
+
+	key, _, _ = data.Floor(35)
+	assert.Equal(t, 30, key)
+	_, _, ok = data.Floor(5)
+	assert.False(t, ok)
+	key, _, _ = data.Ceiling(35)
+	assert.Equal(t, 40, key)
+	_, _, ok = data.Ceiling(85)
+	assert.False(t, ok)
+
+	assert.Equal(t, 2, data.Rank(30))
+	assert.Equal(t, 3, data.Rank(35))
+	key, _, ok = data.Select(7)
+	assert.True(t, ok)
+	assert.Equal(t, 80, key)
+	_, _, ok = data.Select(8)
+	assert.False(t, ok)
+
+	var keys []int
+	for key := range data.Range(20, 60) {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{20, 30, 40, 50}, keys)
+
+	keys = nil
+	for key := range data.Backward() {
+		if key < 50 {
+			break
+		}
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{80, 70, 60, 50}, keys)
+
+	empty := NewBTreeMap[int, int](3)
+	_, _, ok = empty.Min()
+	assert.False(t, ok)
+	_, _, ok = empty.Max()
+	assert.False(t, ok)
+	assert.Panics(t, func() { NewBTreeMap[int, int](1) })
+}
+
+func TestBTreeMapBulkLoad(t *testing.T) {
+	for _, degree := range []int{2, 3, 8} {
+		for size := range 300 {
+			data := NewBTreeMap[int, int](degree)
+			require.True(t, data.BulkLoad(sequence(size)))
+			require.Equal(t, size, data.Size())
+			checkBTree(t, &data)
+
+			for rank := range size {
+				key, value, ok := data.Select(rank)
+				require.True(t, ok)
+				require.Equal(t, rank*2, key)
+				require.Equal(t, rank, value)
+			}
+		}
+	}
+
+	data := NewBTreeMap[int, int](2)
+	assert.False(t, data.BulkLoad(func(yield func(int, int) bool) {
+		_ = yield(2, 0) && yield(1, 0)
+	}))
+
+	data.Insert(1, 1)
+	assert.False(t, data.BulkLoad(sequence(10)))
+}
+
+func TestBTreeMapZeroValue(t *testing.T) {
+	var data BTreeMap[int, int]
+	assert.Zero(t, data.Size())
+	assert.False(t, data.Contains(1))
+	data.Erase(1)
+
+	for key := range 1000 {
+		data.Insert(key, key*10)
+	}
+
+	assert.Equal(t, defaultDegree, data.degree)
+	assert.Equal(t, 1000, data.Size())
+	checkBTree(t, &data)
+
+	snapshot := data.Clone()
+	data.Erase(0)
+	assert.True(t, snapshot.Contains(0))
+
+	var cloned BTreeMap[string, int]
+	clone := cloned.Clone()
+	clone.Insert("b", 1)
+	clone.Insert("a", 2)
+	assert.Equal(t, []string{"a", "b"}, slices.Collect(clone.Keys()))
+	assert.Zero(t, cloned.Size())
+
+	var loaded BTreeMap[int, int]
+	require.True(t, loaded.BulkLoad(sequence(100)))
+	checkBTree(t, &loaded)
+}
+
+func TestBTreeMapClone(t *testing.T) {
+	data := NewBTreeMap[int, int](2)
+	require.True(t, data.BulkLoad(sequence(100)))
+
+	snapshot := data.Clone()
+	for key := range 50 {
+		data.Erase(key * 2)
+		data.Insert(key*2+1, key)
+	}
+	snapshot.Insert(1000, 1000)
+
+	assert.Equal(t, 100, data.Size())
+	assert.Equal(t, 101, snapshot.Size())
+	checkBTree(t, &data)
+	checkBTree(t, &snapshot)
+
+	value, ok := snapshot.Get(0)
+	assert.True(t, ok)
+	assert.Zero(t, value)
+	assert.False(t, data.Contains(0))
+	assert.False(t, data.Contains(1000))
+
+	for rank := range 100 {
+		key, _, _ := snapshot.Select(rank)
+		require.Equal(t, rank*2, key)
+	}
+}
+
+func TestBTreeMapProperties(t *testing.T) {
+	const (
+		operationsNumber = 20_000
+		keysRange        = 500
+	)
+
+	for _, degree := range []int{2, 3, 16} {
+		random := rand.New(rand.NewPCG(uint64(degree), 0))
+		data := NewBTreeMap[int, int](degree)
+		reference := map[int]int{}
+
+		var snapshot BTreeMap[int, int]
+		var snapshotReference map[int]int
+
+		for operation := range operationsNumber {
+			key := random.IntN(keysRange)
+			if random.IntN(3) == 0 {
+				data.Erase(key)
+				delete(reference, key)
+			} else {
+				data.Insert(key, operation)
+				reference[key] = operation
+			}
+
+			require.Equal(t, len(reference), data.Size())
+			value, ok := data.Get(key)
+			expected, found := reference[key]
+			require.Equal(t, found, ok)
+			require.Equal(t, expected, value)
+
+			if operation%500 == 0 {
+				checkBTree(t, &data)
+				checkBTreeContent(t, &data, reference, random.IntN(keysRange))
+
+				if snapshot.root != nil {
+					checkBTree(t, &snapshot)
+					checkBTreeContent(t, &snapshot, snapshotReference, random.IntN(keysRange))
+				}
+
+				snapshot = data.Clone()
+				snapshotReference = maps.Clone(reference)
+			}
+		}
+	}
+}
+
+func checkBTreeContent(t *testing.T, data *BTreeMap[int, int], reference map[int]int, probe int) {
+	keys := make([]int, 0, len(reference))
+	for key := range reference {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	require.Equal(t, keys, append([]int{}, slices.Collect(data.Keys())...))
+	for key, value := range data.All() {
+		require.Equal(t, reference[key], value)
+	}
+
+	idx, found := slices.BinarySearch(keys, probe)
+	require.Equal(t, idx, data.Rank(probe))
+
+	key, _, ok := data.Floor(probe)
+	floorIdx := idx - 1
+	if found {
+		floorIdx = idx
+	}
+	require.Equal(t, floorIdx >= 0, ok)
+	if ok {
+		require.Equal(t, keys[floorIdx], key)
+	}
+
+	key, _, ok = data.Ceiling(probe)
+	require.Equal(t, idx < len(keys), ok)
+	if ok {
+		require.Equal(t, keys[idx], key)
+	}
+
+	to, _ := slices.BinarySearch(keys, probe+30)
+	inRange := []int{}
+	for key := range data.Range(probe, probe+30) {
+		inRange = append(inRange, key)
+	}
+	require.Equal(t, append([]int{}, keys[idx:to]...), inRange)
+
+	for rank, key := range keys {
+		selected, _, ok := data.Select(rank)
+		require.True(t, ok)
+		require.Equal(t, key, selected)
+	}
+}
+
+func checkBTree(t *testing.T, data *BTreeMap[int, int]) {
+	leafDepth := -1
+
+	var check func(node *btreeNode[int, int], depth int, lo, hi *int)
+	check = func(node *btreeNode[int, int], depth int, lo, hi *int) {
+		require.LessOrEqual(t, len(node.keys), data.maxKeys())
+		require.Equal(t, len(node.keys), len(node.values))
+		if node != data.root {
+			require.GreaterOrEqual(t, len(node.keys), data.degree-1)
+		} else {
+			require.NotEmpty(t, node.keys)
+		}
+
+		require.True(t, slices.IsSorted(node.keys))
+		if lo != nil {
+			require.Greater(t, node.keys[0], *lo)
+		}
+		if hi != nil {
+			require.Less(t, node.keys[len(node.keys)-1], *hi)
+		}
+
+		require.Equal(t, countSize(node), node.size)
+
+		if node.leaf() {
+			if leafDepth < 0 {
+				leafDepth = depth
+			}
+			require.Equal(t, leafDepth, depth)
+			return
+		}
+
+		require.Len(t, node.children, len(node.keys)+1)
+		for idx, child := range node.children {
+			var childLo, childHi *int
+			if idx > 0 {
+				childLo = &node.keys[idx-1]
+			} else {
+				childLo = lo
+			}
+			if idx < len(node.keys) {
+				childHi = &node.keys[idx]
+			} else {
+				childHi = hi
+			}
+
+			check(child, depth+1, childLo, childHi)
+		}
+	}
+
+	if data.root != nil {
+		check(data.root, 0, nil, nil)
+	}
+}
+
+// sequence yields even keys with their ranks as values.
+func sequence(size int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for idx := range size {
+			if !yield(idx*2, idx) {
+				return
+			}
+		}
+	}
+}
@@ -1,203 +1,445 @@
 package main
 
 import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // go test -v homework_test.go
 
-type TreeNode struct {
-	key     int
-	value   int
-	lBranch *TreeNode
-	rBranch *TreeNode
+// TreeNode is a node of an AVL tree. size is the number of nodes
+// in the subtree, it makes rank and select logarithmic.
+type TreeNode[K, V any] struct {
+	key     K
+	value   V
+	lBranch *TreeNode[K, V]
+	rBranch *TreeNode[K, V]
+	height  int
+	size    int
 }
 
-type OrderedMap struct {
-	head *TreeNode
-	size int
+// OrderedMap must not be modified while it is iterated.
+// The zero value is an empty map ordered by cmp.Compare, it works for keys
+// whose underlying type is cmp.Ordered and panics on the first Insert otherwise.
+type OrderedMap[K, V any] struct {
+	head    *TreeNode[K, V]
+	compare func(K, K) int
 }
 
-func NewOrderedMap() OrderedMap {
-	return OrderedMap{}
+func NewOrderedMap[K cmp.Ordered, V any]() OrderedMap[K, V] {
+	return NewOrderedMapFunc[K, V](cmp.Compare[K])
 }
 
-func (m *OrderedMap) Insert(key, value int) {
-	if m.head == nil {
-		m.head = &TreeNode{
-			key:   key,
-			value: value,
-		}
-		m.size = 1
-		return
+func NewOrderedMapFunc[K, V any](compare func(K, K) int) OrderedMap[K, V] {
+	return OrderedMap[K, V]{compare: compare}
+}
+
+// orderedCompare is cmp.Compare for a key type that isn't constrained
+// by cmp.Ordered. The kind of the key is checked once, the comparator
+// reads keys as their underlying type, so named types work as well.
+func orderedCompare[K any]() func(K, K) int {
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.Int:
+		return compareAs[int, K]
+	case reflect.Int8:
+		return compareAs[int8, K]
+	case reflect.Int16:
+		return compareAs[int16, K]
+	case reflect.Int32:
+		return compareAs[int32, K]
+	case reflect.Int64:
+		return compareAs[int64, K]
+	case reflect.Uint:
+		return compareAs[uint, K]
+	case reflect.Uint8:
+		return compareAs[uint8, K]
+	case reflect.Uint16:
+		return compareAs[uint16, K]
+	case reflect.Uint32:
+		return compareAs[uint32, K]
+	case reflect.Uint64:
+		return compareAs[uint64, K]
+	case reflect.Uintptr:
+		return compareAs[uintptr, K]
+	case reflect.Float32:
+		return compareAs[float32, K]
+	case reflect.Float64:
+		return compareAs[float64, K]
+	case reflect.String:
+		return compareAs[string, K]
+	default:
+		panic("OrderedMap: zero value requires an ordered key type, use NewOrderedMapFunc")
+	}
+}
+
+// compareAs expects T to be the underlying type of K.
+func compareAs[T cmp.Ordered, K any](lhs, rhs K) int {
+	return cmp.Compare(*(*T)(unsafe.Pointer(&lhs)), *(*T)(unsafe.Pointer(&rhs)))
+}
+
+func (m *OrderedMap[K, V]) Insert(key K, value V) {
+	if m.compare == nil {
+		m.compare = orderedCompare[K]()
 	}
 
-	var node *TreeNode
+	m.head = m.insertNode(m.head, key, value)
+}
 
-	if !m.Contains(key) {
-		m.size += 1
+func (m *OrderedMap[K, V]) Erase(key K) {
+	m.head = m.eraseNode(m.head, key)
+}
 
-		node = m.insertNode(key)
-	} else {
-		node = findNode(m.head, key)
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	node := m.findNode(key)
+	if node == nil {
+		var zero V
+		return zero, false
 	}
 
-	node.value = value
+	return node.value, true
 }
 
-func (m *OrderedMap) Erase(key int) {
-	if m.empty() || !m.Contains(key) {
-		return
+func (m *OrderedMap[K, V]) Contains(key K) bool {
+	return m.findNode(key) != nil
+}
+
+func (m *OrderedMap[K, V]) Size() int {
+	return size(m.head)
+}
+
+func (m *OrderedMap[K, V]) ForEach(action func(K, V)) {
+	for key, value := range m.All() {
+		action(key, value)
 	}
+}
 
-	if m.size == 1 {
-		m.head = nil
-		m.size = 0
-		return
+func (m *OrderedMap[K, V]) Min() (K, V, bool) {
+	node := m.head
+	for node != nil && node.lBranch != nil {
+		node = node.lBranch
 	}
 
-	var replacement *TreeNode
+	return entry(node)
+}
 
-	root := findRootNode(m.head, key)
-	toDelete := findNode(root, key)
+func (m *OrderedMap[K, V]) Max() (K, V, bool) {
+	node := m.head
+	for node != nil && node.rBranch != nil {
+		node = node.rBranch
+	}
 
-	if toDelete.lBranch == nil || toDelete.rBranch == nil {
+	return entry(node)
+}
 
-		if toDelete.lBranch != nil {
-			replacement = toDelete.lBranch
+// Floor returns the greatest key less than or equal to key.
+func (m *OrderedMap[K, V]) Floor(key K) (K, V, bool) {
+	var found *TreeNode[K, V]
+	for node := m.head; node != nil; {
+		result := m.compare(key, node.key)
+		if result == 0 {
+			return entry(node)
+		} else if result < 0 {
+			node = node.lBranch
 		} else {
-			replacement = toDelete.rBranch
+			found = node
+			node = node.rBranch
 		}
-	} else {
-		replacement = findMinAndDetach(toDelete.rBranch)
-		replacement.lBranch = toDelete.lBranch
+	}
+
+	return entry(found)
+}
 
-		if replacement != toDelete.rBranch {
-			replacement.rBranch = toDelete.rBranch
+// Ceiling returns the least key greater than or equal to key.
+func (m *OrderedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var found *TreeNode[K, V]
+	for node := m.head; node != nil; {
+		result := m.compare(key, node.key)
+		if result == 0 {
+			return entry(node)
+		} else if result > 0 {
+			node = node.rBranch
+		} else {
+			found = node
+			node = node.lBranch
 		}
 	}
 
-	if root.lBranch == toDelete {
-		root.lBranch = replacement
-	} else {
-		root.rBranch = replacement
+	return entry(found)
+}
+
+// Rank returns the number of keys less than key.
+func (m *OrderedMap[K, V]) Rank(key K) int {
+	rank := 0
+	for node := m.head; node != nil; {
+		result := m.compare(key, node.key)
+		if result == 0 {
+			return rank + size(node.lBranch)
+		} else if result < 0 {
+			node = node.lBranch
+		} else {
+			rank += size(node.lBranch) + 1
+			node = node.rBranch
+		}
 	}
 
-	m.size -= 1
+	return rank
 }
 
-func (m *OrderedMap) Contains(key int) bool {
-	if m.empty() {
-		return false
+// Select returns the entry with the given zero-based rank.
+func (m *OrderedMap[K, V]) Select(rank int) (K, V, bool) {
+	if rank < 0 || rank >= m.Size() {
+		return entry[K, V](nil)
 	}
 
-	node := findNode(m.head, key)
+	node := m.head
+	for {
+		leftSize := size(node.lBranch)
+		if rank < leftSize {
+			node = node.lBranch
+		} else if rank == leftSize {
+			return entry(node)
+		} else {
+			rank -= leftSize + 1
+			node = node.rBranch
+		}
+	}
+}
 
-	return node != nil
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.head, yield)
+	}
 }
 
-func (m *OrderedMap) Size() int {
-	return m.size
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		descend(m.head, yield)
+	}
 }
 
-func (m *OrderedMap) ForEach(action func(int, int)) {
-	if m.empty() {
-		return
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
 	}
+}
 
-	m.applyAction(m.head, action)
+func (m *OrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
 }
 
-func (m *OrderedMap) insertNode(key int) *TreeNode {
-	rootNode := findRootNode(m.head, key)
-	node := &TreeNode{key: key}
+// Range iterates over keys in [lo, hi) in ascending order.
+func (m *OrderedMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.ascendRange(m.head, lo, hi, yield)
+	}
+}
 
-	if key > rootNode.key {
-		rootNode.rBranch = node
-	} else {
-		rootNode.lBranch = node
+func (m *OrderedMap[K, V]) ascendRange(node *TreeNode[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if node == nil {
+		return true
 	}
 
-	return node
-}
+	aboveLo := m.compare(lo, node.key) <= 0
+	belowHi := m.compare(node.key, hi) < 0
 
-func (m *OrderedMap) applyAction(from *TreeNode, action func(int, int)) {
-	if from.lBranch != nil {
-		m.applyAction(from.lBranch, action)
+	if aboveLo && !m.ascendRange(node.lBranch, lo, hi, yield) {
+		return false
 	}
 
-	action(from.key, from.value)
+	if aboveLo && belowHi && !yield(node.key, node.value) {
+		return false
+	}
 
-	if from.rBranch != nil {
-		m.applyAction(from.rBranch, action)
+	if belowHi {
+		return m.ascendRange(node.rBranch, lo, hi, yield)
 	}
-}
 
-func (m *OrderedMap) empty() bool {
-	return m.Size() == 0
+	return true
 }
 
-func findNode(from *TreeNode, key int) *TreeNode {
-	if from == nil {
-		return nil
+func ascend[K, V any](node *TreeNode[K, V], yield func(K, V) bool) bool {
+	if node == nil {
+		return true
 	}
 
-	if from.key == key {
-		return from
+	return ascend(node.lBranch, yield) && yield(node.key, node.value) && ascend(node.rBranch, yield)
+}
+
+func descend[K, V any](node *TreeNode[K, V], yield func(K, V) bool) bool {
+	if node == nil {
+		return true
 	}
 
-	if from.key > key {
-		return findNode(from.lBranch, key)
+	return descend(node.rBranch, yield) && yield(node.key, node.value) && descend(node.lBranch, yield)
+}
+
+func (m *OrderedMap[K, V]) findNode(key K) *TreeNode[K, V] {
+	node := m.head
+	for node != nil {
+		result := m.compare(key, node.key)
+		if result == 0 {
+			return node
+		} else if result < 0 {
+			node = node.lBranch
+		} else {
+			node = node.rBranch
+		}
 	}
 
-	return findNode(from.rBranch, key)
+	return nil
 }
 
-func findRootNode(from *TreeNode, key int) *TreeNode {
-	var branch *TreeNode
+func (m *OrderedMap[K, V]) insertNode(node *TreeNode[K, V], key K, value V) *TreeNode[K, V] {
+	if node == nil {
+		return &TreeNode[K, V]{key: key, value: value, height: 1, size: 1}
+	}
 
-	if from.key > key {
-		branch = from.lBranch
+	result := m.compare(key, node.key)
+	if result == 0 {
+		node.value = value
+		return node
+	} else if result < 0 {
+		node.lBranch = m.insertNode(node.lBranch, key, value)
 	} else {
-		branch = from.rBranch
+		node.rBranch = m.insertNode(node.rBranch, key, value)
+	}
+
+	return rebalance(node)
+}
+
+func (m *OrderedMap[K, V]) eraseNode(node *TreeNode[K, V], key K) *TreeNode[K, V] {
+	if node == nil {
+		return nil
+	}
+
+	result := m.compare(key, node.key)
+	if result < 0 {
+		node.lBranch = m.eraseNode(node.lBranch, key)
+		return rebalance(node)
+	} else if result > 0 {
+		node.rBranch = m.eraseNode(node.rBranch, key)
+		return rebalance(node)
+	}
+
+	if node.lBranch == nil {
+		return node.rBranch
+	} else if node.rBranch == nil {
+		return node.lBranch
 	}
 
-	if branch == nil || branch.key == key {
-		return from
+	replacement := node.rBranch
+	for replacement.lBranch != nil {
+		replacement = replacement.lBranch
 	}
 
-	return findRootNode(branch, key)
+	replacement.rBranch = detachMin(node.rBranch)
+	replacement.lBranch = node.lBranch
+	return rebalance(replacement)
 }
 
-func findMinAndDetach(from *TreeNode) *TreeNode {
-	if from.lBranch == nil {
-		return from
+func detachMin[K, V any](node *TreeNode[K, V]) *TreeNode[K, V] {
+	if node.lBranch == nil {
+		return node.rBranch
 	}
 
-	minParent := from
-	minEl := minParent.lBranch
+	node.lBranch = detachMin(node.lBranch)
+	return rebalance(node)
+}
 
-	for {
-		if minEl.lBranch == nil {
-			break
+func rebalance[K, V any](node *TreeNode[K, V]) *TreeNode[K, V] {
+	node.update()
+
+	balance := height(node.lBranch) - height(node.rBranch)
+	if balance > 1 {
+		if height(node.lBranch.lBranch) < height(node.lBranch.rBranch) {
+			node.lBranch = rotateLeft(node.lBranch)
+		}
+
+		return rotateRight(node)
+	} else if balance < -1 {
+		if height(node.rBranch.rBranch) < height(node.rBranch.lBranch) {
+			node.rBranch = rotateRight(node.rBranch)
 		}
 
-		minParent = minEl
-		minEl = minParent.lBranch
+		return rotateLeft(node)
+	}
+
+	return node
+}
+
+func rotateLeft[K, V any](node *TreeNode[K, V]) *TreeNode[K, V] {
+	top := node.rBranch
+	node.rBranch = top.lBranch
+	top.lBranch = node
+
+	node.update()
+	top.update()
+	return top
+}
+
+func rotateRight[K, V any](node *TreeNode[K, V]) *TreeNode[K, V] {
+	top := node.lBranch
+	node.lBranch = top.rBranch
+	top.rBranch = node
+
+	node.update()
+	top.update()
+	return top
+}
+
+func (n *TreeNode[K, V]) update() {
+	n.height = 1 + max(height(n.lBranch), height(n.rBranch))
+	n.size = 1 + size(n.lBranch) + size(n.rBranch)
+}
+
+func height[K, V any](node *TreeNode[K, V]) int {
+	if node == nil {
+		return 0
+	}
+
+	return node.height
+}
+
+func size[K, V any](node *TreeNode[K, V]) int {
+	if node == nil {
+		return 0
 	}
 
-	if minEl.rBranch != nil {
-		minParent.lBranch = minEl.rBranch
+	return node.size
+}
+
+func entry[K, V any](node *TreeNode[K, V]) (K, V, bool) {
+	if node == nil {
+		var key K
+		var value V
+		return key, value, false
 	}
 
-	return minEl
+	return node.key, node.value, true
 }
 
 func TestCircularQueue(t *testing.T) {
-	data := NewOrderedMap()
+	data := NewOrderedMap[int, int]()
 	assert.Zero(t, data.Size())
 
 	data.Insert(10, 10)
@@ -242,7 +484,7 @@ func TestCircularQueue(t *testing.T) {
 }
 
 func TestCircularQueueWithCornerCases(t *testing.T) {
-	data := NewOrderedMap()
+	data := NewOrderedMap[int, int]()
 	assert.Zero(t, data.Size())
 
 	data.Insert(10, 10)
@@ -294,3 +536,299 @@ func TestCircularQueueWithCornerCases(t *testing.T) {
 	})
 	assert.True(t, reflect.DeepEqual(expectedKeys, keys))
 }
+
+func TestOrderedMapQueries(t *testing.T) {
+	data := NewOrderedMap[int, string]()
+	for _, key := range []int{40, 10, 30, 20, 50} {
+		data.Insert(key, strings.Repeat("*", key/10))
+	}
+
+	value, ok := data.Get(30)
+	assert.True(t, ok)
+	assert.Equal(t, "***", value)
+	_, ok = data.Get(35)
+	assert.False(t, ok)
+
+	key, _, ok := data.Min()
+	assert.True(t, ok)
+	assert.Equal(t, 10, key)
+	key, _, _ = data.Max()
+	assert.Equal(t, 50, key)
+
+	key, _, ok = data.Floor(35)
+	assert.True(t, ok)
+	assert.Equal(t, 30, key)
+	key, _, _ = data.Floor(30)
+	assert.Equal(t, 30, key)
+	_, _, ok = data.Floor(5)
+	assert.False(t, ok)
+
+	key, _, ok = data.Ceiling(35)
+	assert.True(t, ok)
+	assert.Equal(t, 40, key)
+	_, _, ok = data.Ceiling(55)
+	assert.False(t, ok)
+
+	assert.Equal(t, 0, data.Rank(5))
+	assert.Equal(t, 2, data.Rank(30))
+	assert.Equal(t, 3, data.Rank(35))
+	assert.Equal(t, 5, data.Rank(100))
+
+	key, value, ok = data.Select(1)
+	assert.True(t, ok)
+	assert.Equal(t, 20, key)
+	assert.Equal(t, "**", value)
+	_, _, ok = data.Select(5)
+	assert.False(t, ok)
+	_, _, ok = data.Select(-1)
+	assert.False(t, ok)
+
+	empty := NewOrderedMap[int, int]()
+	_, _, ok = empty.Min()
+	assert.False(t, ok)
+	_, _, ok = empty.Max()
+	assert.False(t, ok)
+}
+
+func TestOrderedMapIterators(t *testing.T) {
+	data := NewOrderedMap[int, int]()
+	for key := range 10 {
+		data.Insert(key, key*key)
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, slices.Collect(data.Keys()))
+	assert.Equal(t, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}, slices.Collect(data.Values()))
+
+	var keys []int
+	for key := range data.Range(3, 7) {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{3, 4, 5, 6}, keys)
+
+	keys = nil
+	for key := range data.Backward() {
+		if key < 7 {
+			break
+		}
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{9, 8, 7}, keys)
+
+	keys = nil
+	for key, value := range data.All() {
+		if value > 10 {
+			break
+		}
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3}, keys)
+
+	for range data.Range(7, 3) {
+		t.Fatal("empty range must not yield")
+	}
+}
+
+func TestOrderedMapWithComparator(t *testing.T) {
+	data := NewOrderedMapFunc[string, int](func(lhs, rhs string) int {
+		return cmp.Compare(strings.ToLower(rhs), strings.ToLower(lhs))
+	})
+
+	data.Insert("b", 1)
+	data.Insert("A", 2)
+	data.Insert("c", 3)
+	data.Insert("B", 4)
+
+	assert.Equal(t, 3, data.Size())
+	assert.Equal(t, []string{"c", "b", "A"}, slices.Collect(data.Keys()))
+
+	value, ok := data.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 4, value)
+}
+
+func TestOrderedMapZeroValue(t *testing.T) {
+	var data OrderedMap[int, string]
+	assert.Zero(t, data.Size())
+	assert.False(t, data.Contains(1))
+	data.Erase(1)
+
+	data.Insert(2, "two")
+	data.Insert(-1, "minus one")
+	data.Insert(10, "ten")
+	data.Erase(2)
+
+	assert.Equal(t, []int{-1, 10}, slices.Collect(data.Keys()))
+
+	type name string
+	var names OrderedMap[name, int]
+	names.Insert("bob", 1)
+	names.Insert("alice", 2)
+	assert.Equal(t, []name{"alice", "bob"}, slices.Collect(names.Keys()))
+
+	var floats OrderedMap[float64, int]
+	floats.Insert(2.5, 1)
+	floats.Insert(-0.5, 2)
+	assert.Equal(t, []float64{-0.5, 2.5}, slices.Collect(floats.Keys()))
+
+	var bytes OrderedMap[int8, int]
+	bytes.Insert(100, 1)
+	bytes.Insert(-100, 2)
+	bytes.Insert(0, 3)
+	assert.Equal(t, []int8{-100, 0, 100}, slices.Collect(bytes.Keys()))
+
+	var unordered OrderedMap[struct{}, int]
+	assert.Panics(t, func() {
+		unordered.Insert(struct{}{}, 1)
+	})
+}
+
+func TestOrderedMapSortedInputStaysBalanced(t *testing.T) {
+	const keysNumber = 1 << 16
+
+	data := NewOrderedMap[int, int]()
+	for key := range keysNumber {
+		data.Insert(key, key)
+	}
+
+	// An AVL tree is at most ~1.44 * log2(n) high.
+	assert.LessOrEqual(t, height(data.head), 24)
+	checkTree(t, &data)
+
+	for key := range keysNumber / 2 {
+		data.Erase(key)
+	}
+
+	assert.Equal(t, keysNumber/2, data.Size())
+	assert.LessOrEqual(t, height(data.head), 23)
+	checkTree(t, &data)
+}
+
+// referenceMap is a sorted slice, it is slow but obviously correct.
+type referenceMap struct {
+	keys   []int
+	values map[int]int
+}
+
+func (r *referenceMap) insert(key, value int) {
+	if _, found := r.values[key]; !found {
+		idx, _ := slices.BinarySearch(r.keys, key)
+		r.keys = slices.Insert(r.keys, idx, key)
+	}
+
+	r.values[key] = value
+}
+
+func (r *referenceMap) erase(key int) {
+	if idx, found := slices.BinarySearch(r.keys, key); found {
+		r.keys = slices.Delete(r.keys, idx, idx+1)
+		delete(r.values, key)
+	}
+}
+
+func TestOrderedMapProperties(t *testing.T) {
+	const (
+		operationsNumber = 20_000
+		keysRange        = 500
+	)
+
+	for seed := range uint64(5) {
+		random := rand.New(rand.NewPCG(seed, seed))
+		data := NewOrderedMap[int, int]()
+		reference := referenceMap{values: make(map[int]int)}
+
+		for operation := range operationsNumber {
+			key := random.IntN(keysRange)
+			if random.IntN(3) == 0 {
+				data.Erase(key)
+				reference.erase(key)
+			} else {
+				data.Insert(key, operation)
+				reference.insert(key, operation)
+			}
+
+			require.Equal(t, len(reference.keys), data.Size())
+
+			probe := random.IntN(keysRange+20) - 10
+			checkQueries(t, &data, &reference, probe)
+
+			if operation%1000 == 0 {
+				checkTree(t, &data)
+				require.Equal(t, reference.keys, slices.Collect(data.Keys()))
+			}
+		}
+	}
+}
+
+func checkQueries(t *testing.T, data *OrderedMap[int, int], reference *referenceMap, probe int) {
+	keys := reference.keys
+	idx, found := slices.BinarySearch(keys, probe)
+
+	value, ok := data.Get(probe)
+	require.Equal(t, found, ok)
+	require.Equal(t, reference.values[probe], value)
+	require.Equal(t, idx, data.Rank(probe))
+
+	key, _, ok := data.Floor(probe)
+	floorIdx := idx - 1
+	if found {
+		floorIdx = idx
+	}
+	require.Equal(t, floorIdx >= 0, ok)
+	if ok {
+		require.Equal(t, keys[floorIdx], key)
+	}
+
+	key, _, ok = data.Ceiling(probe)
+	require.Equal(t, idx < len(keys), ok)
+	if ok {
+		require.Equal(t, keys[idx], key)
+	}
+
+	if len(keys) > 0 {
+		rank := int(uint(probe) % uint(len(keys)))
+		key, value, ok = data.Select(rank)
+		require.True(t, ok)
+		require.Equal(t, keys[rank], key)
+		require.Equal(t, reference.values[key], value)
+
+		key, _, _ = data.Min()
+		require.Equal(t, keys[0], key)
+		key, _, _ = data.Max()
+		require.Equal(t, keys[len(keys)-1], key)
+	}
+
+	hi := probe + 15
+	from := sort.SearchInts(keys, probe)
+	to := sort.SearchInts(keys, hi)
+	inRange := []int{}
+	for key := range data.Range(probe, hi) {
+		inRange = append(inRange, key)
+	}
+	require.Equal(t, append([]int{}, keys[from:to]...), inRange)
+}
+
+func checkTree(t *testing.T, data *OrderedMap[int, int]) {
+	var check func(node *TreeNode[int, int], lo, hi *int)
+	check = func(node *TreeNode[int, int], lo, hi *int) {
+		if node == nil {
+			return
+		}
+
+		if lo != nil {
+			require.Greater(t, node.key, *lo)
+		}
+		if hi != nil {
+			require.Less(t, node.key, *hi)
+		}
+
+		check(node.lBranch, lo, &node.key)
+		check(node.rBranch, &node.key, hi)
+
+		require.Equal(t, 1+max(height(node.lBranch), height(node.rBranch)), node.height)
+		require.Equal(t, 1+size(node.lBranch)+size(node.rBranch), node.size)
+		require.LessOrEqual(t, height(node.lBranch)-height(node.rBranch), 1)
+		require.GreaterOrEqual(t, height(node.lBranch)-height(node.rBranch), -1)
+	}
+
+	check(data.head, nil, nil)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// go test -bench=. -benchmem .
+
+const benchmarkKeysNumber = 1_000_000
+
+var benchmarkSink int
+
+type benchmarkData struct {
+	keys   []int // in random order
+	sorted []int
+
+	orderedMap OrderedMap[int, int]
+	btree      BTreeMap[int, int]
+}
+
+var loadBenchmarkData = sync.OnceValue(func() *benchmarkData {
+	random := rand.New(rand.NewPCG(42, 42))
+
+	data := &benchmarkData{
+		keys:       make([]int, benchmarkKeysNumber),
+		orderedMap: NewOrderedMap[int, int](),
+		btree:      NewBTreeMap[int, int](32),
+	}
+
+	for idx := range data.keys {
+		data.keys[idx] = random.Int()
+		data.orderedMap.Insert(data.keys[idx], idx)
+		data.btree.Insert(data.keys[idx], idx)
+	}
+
+	data.sorted = slices.Clone(data.keys)
+	slices.Sort(data.sorted)
+	return data
+})
+
+func BenchmarkGet(b *testing.B) {
+	data := loadBenchmarkData()
+
+	b.Run("OrderedMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			value, _ := data.orderedMap.Get(data.keys[i%benchmarkKeysNumber])
+			benchmarkSink += value
+		}
+	})
+
+	b.Run("BTreeMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			value, _ := data.btree.Get(data.keys[i%benchmarkKeysNumber])
+			benchmarkSink += value
+		}
+	})
+
+	b.Run("SortedSlice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			idx, _ := slices.BinarySearch(data.sorted, data.keys[i%benchmarkKeysNumber])
+			benchmarkSink += idx
+		}
+	})
+}
+
+func BenchmarkIterate(b *testing.B) {
+	data := loadBenchmarkData()
+
+	b.Run("OrderedMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for key := range data.orderedMap.Keys() {
+				benchmarkSink += key
+			}
+		}
+	})
+
+	b.Run("BTreeMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for key := range data.btree.Keys() {
+				benchmarkSink += key
+			}
+		}
+	})
+
+	b.Run("SortedSlice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, key := range data.sorted {
+				benchmarkSink += key
+			}
+		}
+	})
+}
+
+// Inserting into a sorted slice moves half of it on average,
+// so it isn't compared here.
+func BenchmarkInsertErase(b *testing.B) {
+	data := loadBenchmarkData()
+	random := rand.New(rand.NewPCG(1, 1))
+
+	b.Run("OrderedMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			key := random.Int()
+			data.orderedMap.Insert(key, i)
+			data.orderedMap.Erase(key)
+		}
+	})
+
+	b.Run("BTreeMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			key := random.Int()
+			data.btree.Insert(key, i)
+			data.btree.Erase(key)
+		}
+	})
+}
+
+func BenchmarkBuildFromSorted(b *testing.B) {
+	data := loadBenchmarkData()
+
+	b.Run("OrderedMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			orderedMap := NewOrderedMap[int, int]()
+			for idx, key := range data.sorted {
+				orderedMap.Insert(key, idx)
+			}
+		}
+	})
+
+	b.Run("BTreeMap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			btree := NewBTreeMap[int, int](32)
+			for idx, key := range data.sorted {
+				btree.Insert(key, idx)
+			}
+		}
+	})
+
+	b.Run("BTreeMapBulkLoad", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			btree := NewBTreeMap[int, int](32)
+			btree.BulkLoad(slices.All(data.sorted))
+		}
+	})
+}
+
+func BenchmarkCloneAndInsert(b *testing.B) {
+	data := loadBenchmarkData()
+	random := rand.New(rand.NewPCG(2, 2))
+
+	for i := 0; i < b.N; i++ {
+		snapshot := data.btree.Clone()
+		snapshot.Insert(random.Int(), i)
+	}
+}
+
+func BenchmarkBTreeMapDegree(b *testing.B) {
+	data := loadBenchmarkData()
+
+	for _, degree := range []int{2, 8, 32, 128} {
+		btree := NewBTreeMap[int, int](degree)
+		btree.BulkLoad(slices.All(data.sorted))
+
+		b.Run(fmt.Sprint(degree), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				value, _ := btree.Get(data.keys[i%benchmarkKeysNumber])
+				benchmarkSink += value
+			}
+		})
+	}
+}
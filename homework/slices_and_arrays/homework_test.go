@@ -1,77 +1,213 @@
 package main
 
 import (
+	"iter"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // go test -v homework_test.go
 
-type CircularQueue[T int | int16 | int32 | int64] struct {
+type DequeMode int
+
+const (
+	Growing     DequeMode = iota // the capacity doubles and stays a power of two
+	Bounded                      // pushes fail when the deque is full
+	Overwriting                  // pushes replace the element at the other end, for rolling windows
+)
+
+// Deque is a ring buffer. The zero value is an empty Growing deque,
+// NewDeque makes a Bounded one like the former CircularQueue. Popped
+// slots are cleared, so the buffer doesn't keep popped values reachable.
+type Deque[T any] struct {
 	values      []T
 	headIdx     int
-	tailIdx     int
 	currentSize int
+	mode        DequeMode
+}
+
+func NewDeque[T any](capacity int) Deque[T] {
+	return NewDequeWithMode[T](capacity, Bounded)
+}
+
+func NewDequeWithMode[T any](capacity int, mode DequeMode) Deque[T] {
+	if mode == Growing {
+		capacity = ceilPowerOfTwo(capacity)
+	}
+
+	return Deque[T]{
+		values: make([]T, capacity),
+		mode:   mode,
+	}
 }
 
-func NewCircularQueue[T int | int16 | int32 | int64](size int) CircularQueue[T] {
-	return CircularQueue[T]{
-		values: make([]T, size),
+func ceilPowerOfTwo(number int) int {
+	power := 1
+	for power < number {
+		power *= 2
 	}
+
+	return power
 }
 
-func (q *CircularQueue[T]) Push(value T) bool {
-	if q.Full() {
+// PushBack returns false only for a full Bounded deque.
+func (q *Deque[T]) PushBack(value T) bool {
+	if !q.makeRoom(false) {
 		return false
 	}
 
-	q.values[q.tailIdx] = value
-	q.tailIdx = q.nextIdx(q.tailIdx)
+	q.values[q.physicalIdx(q.currentSize)] = value
 	q.currentSize += 1
 
 	return true
 }
 
-func (q *CircularQueue[T]) Pop() bool {
-	if q.Empty() {
+func (q *Deque[T]) PushFront(value T) bool {
+	if !q.makeRoom(true) {
 		return false
 	}
 
-	q.headIdx = q.nextIdx(q.headIdx)
-	q.currentSize -= 1
+	q.headIdx = q.prevIdx(q.headIdx)
+	q.values[q.headIdx] = value
+	q.currentSize += 1
 
 	return true
 }
 
-func (q *CircularQueue[T]) Front() T {
+// makeRoom frees a slot for a push to the front or to the back.
+func (q *Deque[T]) makeRoom(front bool) bool {
+	if !q.Full() {
+		return true
+	}
+
+	switch q.mode {
+	case Growing:
+		q.grow()
+		return true
+	case Overwriting:
+		if len(q.values) == 0 {
+			return false
+		}
+
+		if front {
+			q.PopBack()
+		} else {
+			q.PopFront()
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *Deque[T]) grow() {
+	values := make([]T, max(1, 2*len(q.values)))
+	for i := range q.currentSize {
+		values[i] = q.values[q.physicalIdx(i)]
+	}
+
+	q.values = values
+	q.headIdx = 0
+}
+
+func (q *Deque[T]) PopFront() (T, bool) {
+	var zero T
 	if q.Empty() {
-		return -1
+		return zero, false
 	}
 
-	return q.values[q.headIdx]
+	value := q.values[q.headIdx]
+	q.values[q.headIdx] = zero // doesn't keep the popped value reachable
+	q.headIdx = q.nextIdx(q.headIdx)
+	q.currentSize -= 1
+
+	return value, true
 }
 
-func (q *CircularQueue[T]) Back() T {
+func (q *Deque[T]) PopBack() (T, bool) {
+	var zero T
 	if q.Empty() {
-		return -1
+		return zero, false
 	}
 
-	backIdx := q.prevIdx(q.tailIdx)
+	backIdx := q.physicalIdx(q.currentSize - 1)
+	value := q.values[backIdx]
+	q.values[backIdx] = zero
+	q.currentSize -= 1
+
+	return value, true
+}
+
+func (q *Deque[T]) Front() (T, bool) {
+	return q.At(0)
+}
+
+func (q *Deque[T]) Back() (T, bool) {
+	return q.At(q.currentSize - 1)
+}
+
+// At returns the element at the index counted from the front.
+func (q *Deque[T]) At(idx int) (T, bool) {
+	if idx < 0 || idx >= q.currentSize {
+		var zero T
+		return zero, false
+	}
+
+	return q.values[q.physicalIdx(idx)], true
+}
+
+func (q *Deque[T]) Len() int {
+	return q.currentSize
+}
 
-	return q.values[backIdx]
+func (q *Deque[T]) Cap() int {
+	return len(q.values)
 }
 
-func (q *CircularQueue[T]) Empty() bool {
+func (q *Deque[T]) Empty() bool {
 	return q.currentSize == 0
 }
 
-func (q *CircularQueue[T]) Full() bool {
+// Full means that the next push fails, grows or overwrites, depending on the mode.
+func (q *Deque[T]) Full() bool {
 	return q.currentSize == len(q.values)
 }
 
-func (q *CircularQueue[T]) nextIdx(idx int) int {
+func (q *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < q.currentSize; i++ {
+			if !yield(q.values[q.physicalIdx(i)]) {
+				return
+			}
+		}
+	}
+}
+
+func (q *Deque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := q.currentSize - 1; i >= 0; i-- {
+			if !yield(q.values[q.physicalIdx(i)]) {
+				return
+			}
+		}
+	}
+}
+
+func (q *Deque[T]) physicalIdx(idx int) int {
+	idx += q.headIdx
+	if idx >= len(q.values) {
+		return idx - len(q.values)
+	}
+
+	return idx
+}
+
+func (q *Deque[T]) nextIdx(idx int) int {
 	idx += 1
 
 	if idx > len(q.values)-1 {
@@ -81,7 +217,7 @@ func (q *CircularQueue[T]) nextIdx(idx int) int {
 	return idx
 }
 
-func (q *CircularQueue[T]) prevIdx(idx int) int {
+func (q *Deque[T]) prevIdx(idx int) int {
 	idx -= 1
 
 	if idx < 0 {
@@ -93,96 +229,265 @@ func (q *CircularQueue[T]) prevIdx(idx int) int {
 
 func TestCircularIntQueue(t *testing.T) {
 	const queueSize = 3
-	queue := NewCircularQueue[int](queueSize)
+	queue := NewDeque[int](queueSize)
 
 	assert.True(t, queue.Empty())
 	assert.False(t, queue.Full())
 
-	assert.Equal(t, -1, queue.Front())
-	assert.Equal(t, -1, queue.Back())
-	assert.False(t, queue.Pop())
+	_, ok := queue.Front()
+	assert.False(t, ok)
+	_, ok = queue.Back()
+	assert.False(t, ok)
+	_, ok = queue.PopFront()
+	assert.False(t, ok)
 
-	assert.True(t, queue.Push(1))
-	assert.True(t, queue.Push(2))
-	assert.True(t, queue.Push(3))
-	assert.False(t, queue.Push(4))
+	assert.True(t, queue.PushBack(1))
+	assert.True(t, queue.PushBack(2))
+	assert.True(t, queue.PushBack(3))
+	assert.False(t, queue.PushBack(4))
 
 	assert.True(t, reflect.DeepEqual([]int{1, 2, 3}, queue.values))
 
 	assert.False(t, queue.Empty())
 	assert.True(t, queue.Full())
 
-	assert.Equal(t, 1, queue.Front())
-	assert.Equal(t, 3, queue.Back())
+	assertFront(t, 1, &queue)
+	assertBack(t, 3, &queue)
 
-	assert.True(t, queue.Pop())
+	assertPopFront(t, 1, &queue)
 	assert.False(t, queue.Empty())
 	assert.False(t, queue.Full())
-	assert.True(t, queue.Push(4))
+	assert.True(t, queue.PushBack(4))
 
 	assert.True(t, reflect.DeepEqual([]int{4, 2, 3}, queue.values))
 
-	assert.Equal(t, 2, queue.Front())
-	assert.Equal(t, 4, queue.Back())
+	assertFront(t, 2, &queue)
+	assertBack(t, 4, &queue)
 
-	assert.True(t, queue.Pop())
-	assert.True(t, queue.Pop())
-	assert.True(t, queue.Pop())
-	assert.False(t, queue.Pop())
+	assertPopFront(t, 2, &queue)
+	assertPopFront(t, 3, &queue)
+	assertPopFront(t, 4, &queue)
+	_, ok = queue.PopFront()
+	assert.False(t, ok)
 
 	assert.True(t, queue.Empty())
 	assert.False(t, queue.Full())
 
-	assert.True(t, queue.Push(1))
-	assert.True(t, reflect.DeepEqual([]int{4, 1, 3}, queue.values))
-	assert.Equal(t, 1, queue.Front())
-	assert.Equal(t, 1, queue.Back())
+	// popped values are cleared, so they aren't kept reachable
+	assert.True(t, queue.PushBack(1))
+	assert.True(t, reflect.DeepEqual([]int{0, 1, 0}, queue.values))
+	assertFront(t, 1, &queue)
+	assertBack(t, 1, &queue)
 }
 
 func TestCircularInt32Queue(t *testing.T) {
 	const queueSize = 3
-	queue := NewCircularQueue[int32](queueSize)
+	queue := NewDeque[int32](queueSize)
 
 	assert.True(t, queue.Empty())
 	assert.False(t, queue.Full())
 
-	assert.Equal(t, int32(-1), queue.Front())
-	assert.Equal(t, int32(-1), queue.Back())
-	assert.False(t, queue.Pop())
+	_, ok := queue.Front()
+	assert.False(t, ok)
+	_, ok = queue.Back()
+	assert.False(t, ok)
+	_, ok = queue.PopFront()
+	assert.False(t, ok)
 
-	assert.True(t, queue.Push(1))
-	assert.True(t, queue.Push(2))
-	assert.True(t, queue.Push(3))
-	assert.False(t, queue.Push(4))
+	assert.True(t, queue.PushBack(1))
+	assert.True(t, queue.PushBack(2))
+	assert.True(t, queue.PushBack(3))
+	assert.False(t, queue.PushBack(4))
 
 	assert.True(t, reflect.DeepEqual([]int32{1, 2, 3}, queue.values))
 
 	assert.False(t, queue.Empty())
 	assert.True(t, queue.Full())
 
-	assert.Equal(t, int32(1), queue.Front())
-	assert.Equal(t, int32(3), queue.Back())
+	assertFront(t, int32(1), &queue)
+	assertBack(t, int32(3), &queue)
 
-	assert.True(t, queue.Pop())
+	assertPopFront(t, int32(1), &queue)
 	assert.False(t, queue.Empty())
 	assert.False(t, queue.Full())
-	assert.True(t, queue.Push(4))
+	assert.True(t, queue.PushBack(4))
 
 	assert.True(t, reflect.DeepEqual([]int32{4, 2, 3}, queue.values))
 
-	assert.Equal(t, int32(2), queue.Front())
-	assert.Equal(t, int32(4), queue.Back())
+	assertFront(t, int32(2), &queue)
+	assertBack(t, int32(4), &queue)
 
-	assert.True(t, queue.Pop())
-	assert.True(t, queue.Pop())
-	assert.True(t, queue.Pop())
-	assert.False(t, queue.Pop())
+	assertPopFront(t, int32(2), &queue)
+	assertPopFront(t, int32(3), &queue)
+	assertPopFront(t, int32(4), &queue)
+	_, ok = queue.PopFront()
+	assert.False(t, ok)
 
 	assert.True(t, queue.Empty())
 	assert.False(t, queue.Full())
 
-	assert.True(t, queue.Push(1))
-	assert.True(t, reflect.DeepEqual([]int32{4, 1, 3}, queue.values))
-	assert.Equal(t, int32(1), queue.Front())
-	assert.Equal(t, int32(1), queue.Back())
+	assert.True(t, queue.PushBack(1))
+	assert.True(t, reflect.DeepEqual([]int32{0, 1, 0}, queue.values))
+	assertFront(t, int32(1), &queue)
+	assertBack(t, int32(1), &queue)
+}
+
+func assertFront[T any](t *testing.T, expected T, queue *Deque[T]) {
+	t.Helper()
+	value, ok := queue.Front()
+	assert.True(t, ok)
+	assert.Equal(t, expected, value)
+}
+
+func assertBack[T any](t *testing.T, expected T, queue *Deque[T]) {
+	t.Helper()
+	value, ok := queue.Back()
+	assert.True(t, ok)
+	assert.Equal(t, expected, value)
+}
+
+func assertPopFront[T any](t *testing.T, expected T, queue *Deque[T]) {
+	t.Helper()
+	value, ok := queue.PopFront()
+	assert.True(t, ok)
+	assert.Equal(t, expected, value)
+}
+
+func TestDequeWithAnyType(t *testing.T) {
+	queue := NewDeque[string](4)
+	assert.True(t, queue.PushBack("b"))
+	assert.True(t, queue.PushFront("a"))
+	assert.True(t, queue.PushBack("c"))
+	assert.True(t, queue.PushFront("z"))
+	assert.False(t, queue.PushFront("y"))
+
+	assert.Equal(t, []string{"z", "a", "b", "c"}, slices.Collect(queue.All()))
+	assert.Equal(t, []string{"c", "b", "a", "z"}, slices.Collect(queue.Backward()))
+
+	value, ok := queue.At(1)
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+	_, ok = queue.At(4)
+	assert.False(t, ok)
+	_, ok = queue.At(-1)
+	assert.False(t, ok)
+
+	value, ok = queue.PopBack()
+	assert.True(t, ok)
+	assert.Equal(t, "c", value)
+	assertPopFront(t, "z", &queue)
+	assert.Equal(t, []string{"a", "b"}, slices.Collect(queue.All()))
+
+	type point struct{ X, Y int }
+	points := NewDeque[*point](1)
+	_, ok = points.Front()
+	assert.False(t, ok) // nil can be a value, so emptiness isn't a sentinel
+	assert.True(t, points.PushBack(nil))
+	front, ok := points.Front()
+	assert.True(t, ok)
+	assert.Nil(t, front)
+}
+
+func TestGrowingDeque(t *testing.T) {
+	queue := NewDequeWithMode[int](3, Growing)
+	assert.Equal(t, 4, queue.Cap())
+
+	for i := range 10 {
+		assert.True(t, queue.PushFront(-i))
+		assert.True(t, queue.PushBack(i))
+	}
+
+	assert.Equal(t, 20, queue.Len())
+	assert.Equal(t, 32, queue.Cap())
+	assert.Equal(t, []int{-9, -8, -7, -6, -5, -4, -3, -2, -1, 0, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, slices.Collect(queue.All()))
+
+	empty := NewDequeWithMode[int](0, Growing)
+	assert.True(t, empty.PushBack(1))
+	assert.True(t, empty.PushBack(2))
+	assert.True(t, empty.PushBack(3))
+	assert.Equal(t, 4, empty.Cap())
+
+	var zero Deque[string]
+	assert.True(t, zero.Empty())
+	assert.True(t, zero.PushBack("b"))
+	assert.True(t, zero.PushFront("a"))
+	assert.True(t, zero.PushBack("c"))
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(zero.All()))
+	assert.Equal(t, 4, zero.Cap())
+}
+
+func TestOverwritingDeque(t *testing.T) {
+	const windowSize = 3
+	window := NewDequeWithMode[float64](windowSize, Overwriting)
+
+	average := func() float64 {
+		var sum float64
+		for value := range window.All() {
+			sum += value
+		}
+
+		return sum / float64(window.Len())
+	}
+
+	var averages []float64
+	for _, value := range []float64{1, 2, 3, 4, 5, 6} {
+		assert.True(t, window.PushBack(value))
+		averages = append(averages, average())
+	}
+
+	assert.Equal(t, []float64{1, 1.5, 2, 3, 4, 5}, averages)
+	assert.Equal(t, windowSize, window.Len())
+
+	assert.True(t, window.PushFront(0)) // replaces the back
+	assert.Equal(t, []float64{0, 4, 5}, slices.Collect(window.All()))
+
+	empty := NewDequeWithMode[int](0, Overwriting)
+	assert.False(t, empty.PushBack(1))
+}
+
+func TestDequeAgainstSlice(t *testing.T) {
+	for _, mode := range []DequeMode{Bounded, Growing, Overwriting} {
+		queue := NewDequeWithMode[int](5, mode)
+		var model []int
+
+		for i := range 1000 {
+			switch i * 7 % 5 {
+			case 0, 1:
+				rejected := len(model) == 5 && mode == Bounded
+				require.Equal(t, !rejected, queue.PushBack(i))
+				switch {
+				case len(model) < 5 || mode == Growing:
+					model = append(model, i)
+				case mode == Overwriting:
+					model = append(model[1:], i)
+				}
+			case 2:
+				rejected := len(model) == 5 && mode == Bounded
+				require.Equal(t, !rejected, queue.PushFront(i))
+				switch {
+				case len(model) < 5 || mode == Growing:
+					model = append([]int{i}, model...)
+				case mode == Overwriting:
+					model = append([]int{i}, model[:len(model)-1]...)
+				}
+			case 3:
+				value, ok := queue.PopBack()
+				require.Equal(t, len(model) > 0, ok)
+				if ok {
+					require.Equal(t, model[len(model)-1], value)
+					model = model[:len(model)-1]
+				}
+			default:
+				value, ok := queue.PopFront()
+				require.Equal(t, len(model) > 0, ok)
+				if ok {
+					require.Equal(t, model[0], value)
+					model = model[1:]
+				}
+			}
+
+			require.Equal(t, len(model), queue.Len())
+			require.True(t, slices.Equal(model, slices.Collect(queue.All())))
+		}
+	}
 }
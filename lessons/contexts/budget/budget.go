@@ -0,0 +1,94 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInsufficientBudget = errors.New("deadline budget is too small")
+
+// Remaining returns the time left until the deadline of ctx.
+// ok is false when ctx has no deadline.
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+
+	return time.Until(deadline), true
+}
+
+// Require fails fast when the remaining budget is smaller than minimum.
+// Contexts without a deadline always have enough budget.
+func Require(ctx context.Context, minimum time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	remaining, ok := Remaining(ctx)
+	if !ok || remaining >= minimum {
+		return nil
+	}
+
+	return insufficient(remaining, minimum)
+}
+
+type options struct {
+	minimum time.Duration
+}
+
+type Option func(*options)
+
+// WithMinimum cancels the derived context right away when its budget
+// is smaller than minimum, so callees don't start work they can't finish.
+func WithMinimum(minimum time.Duration) Option {
+	return func(o *options) {
+		o.minimum = minimum
+	}
+}
+
+// Reserve derives a context that ends reserve earlier than ctx,
+// leaving the caller time for its own post-processing.
+func Reserve(ctx context.Context, reserve time.Duration, opts ...Option) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	return derive(ctx, deadline.Add(-reserve), opts)
+}
+
+// Fraction derives a context that gets the given part (0 < fraction <= 1)
+// of the remaining budget of ctx.
+func Fraction(ctx context.Context, fraction float64, opts ...Option) (context.Context, context.CancelFunc) {
+	remaining, ok := Remaining(ctx)
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	fraction = min(max(fraction, 0), 1)
+	part := time.Duration(float64(remaining) * fraction)
+	return derive(ctx, time.Now().Add(part), opts)
+}
+
+func derive(ctx context.Context, deadline time.Time, opts []Option) (context.Context, context.CancelFunc) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	available := time.Until(deadline)
+	if o.minimum > 0 && available < o.minimum {
+		child, cancel := context.WithCancelCause(ctx)
+		cancel(insufficient(available, o.minimum))
+		return child, func() { cancel(nil) }
+	}
+
+	return context.WithDeadline(ctx, deadline)
+}
+
+func insufficient(remaining, minimum time.Duration) error {
+	return fmt.Errorf("%w: %s left, %s required", ErrInsufficientBudget, max(remaining, 0), minimum)
+}
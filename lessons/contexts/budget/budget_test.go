@@ -0,0 +1,175 @@
+package budget
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+func TestRemaining(t *testing.T) {
+	_, ok := Remaining(context.Background())
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	remaining, ok := Remaining(ctx)
+	assert.True(t, ok)
+	assert.InDelta(t, time.Second, remaining, float64(50*time.Millisecond))
+}
+
+func TestReserve(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	child, cancelChild := Reserve(ctx, 300*time.Millisecond)
+	defer cancelChild()
+
+	parentDeadline, _ := ctx.Deadline()
+	childDeadline, ok := child.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, parentDeadline.Add(-300*time.Millisecond), childDeadline)
+
+	noDeadline, cancelNoDeadline := Reserve(context.Background(), time.Second)
+	defer cancelNoDeadline()
+	_, ok = noDeadline.Deadline()
+	assert.False(t, ok)
+}
+
+func TestFraction(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	child, cancelChild := Fraction(ctx, 0.25)
+	defer cancelChild()
+
+	remaining, ok := Remaining(child)
+	assert.True(t, ok)
+	assert.InDelta(t, 250*time.Millisecond, remaining, float64(50*time.Millisecond))
+
+	whole, cancelWhole := Fraction(ctx, 2)
+	defer cancelWhole()
+
+	parentDeadline, _ := ctx.Deadline()
+	wholeDeadline, _ := whole.Deadline()
+	assert.WithinDuration(t, parentDeadline, wholeDeadline, 50*time.Millisecond)
+}
+
+func TestMinimumCancelsEarly(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	child, cancelChild := Reserve(ctx, 80*time.Millisecond, WithMinimum(50*time.Millisecond))
+	defer cancelChild()
+
+	assert.ErrorIs(t, child.Err(), context.Canceled)
+	assert.ErrorIs(t, context.Cause(child), ErrInsufficientBudget)
+	assert.NoError(t, ctx.Err())
+
+	child, cancelChild = Fraction(ctx, 0.9, WithMinimum(50*time.Millisecond))
+	defer cancelChild()
+	assert.NoError(t, child.Err())
+}
+
+func TestRequire(t *testing.T) {
+	assert.NoError(t, Require(context.Background(), time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, Require(ctx, 10*time.Millisecond))
+	assert.ErrorIs(t, Require(ctx, time.Second), ErrInsufficientBudget)
+
+	cancel()
+	assert.ErrorIs(t, Require(ctx, 0), context.Canceled)
+}
+
+func TestHeader(t *testing.T) {
+	header := http.Header{}
+	_, ok := ParseHeader(header)
+	assert.False(t, ok)
+
+	SetHeader(header, 1500*time.Millisecond)
+	assert.Equal(t, "1500", header.Get(BudgetHeader))
+
+	remaining, ok := ParseHeader(header)
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, remaining)
+
+	SetHeader(header, -time.Second)
+	assert.Equal(t, "0", header.Get(BudgetHeader))
+
+	header.Set(BudgetHeader, "abc")
+	_, ok = ParseHeader(header)
+	assert.False(t, ok)
+}
+
+func TestBudgetPropagationOverHTTP(t *testing.T) {
+	var backendRemaining time.Duration
+	backend := httptest.NewServer(Middleware(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backendRemaining, _ = Remaining(r.Context())
+	})))
+	defer backend.Close()
+
+	client := &http.Client{Transport: &Transport{Minimum: 10 * time.Millisecond}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	callCtx, cancelCall := Reserve(ctx, 200*time.Millisecond)
+	defer cancelCall()
+
+	request, err := http.NewRequestWithContext(callCtx, http.MethodGet, backend.URL, nil)
+	require.NoError(t, err)
+
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Greater(t, backendRemaining, 600*time.Millisecond)
+	assert.LessOrEqual(t, backendRemaining, 800*time.Millisecond)
+}
+
+func TestTransportFailsFast(t *testing.T) {
+	var called bool
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer backend.Close()
+
+	client := &http.Client{Transport: &Transport{Minimum: time.Second}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, backend.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(request)
+	assert.ErrorIs(t, err, ErrInsufficientBudget)
+	assert.False(t, called)
+}
+
+func TestMiddlewareRejectsSmallBudget(t *testing.T) {
+	var called bool
+	handler := Middleware(50 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	SetHeader(request.Header, 10*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	assert.False(t, called)
+}
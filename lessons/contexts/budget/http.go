@@ -0,0 +1,88 @@
+package budget
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// BudgetHeader carries the remaining budget in milliseconds. A relative
+// value is used instead of an absolute deadline because the clocks of
+// services are not synchronized.
+const BudgetHeader = "X-Request-Budget"
+
+func SetHeader(header http.Header, remaining time.Duration) {
+	header.Set(BudgetHeader, strconv.FormatInt(max(remaining.Milliseconds(), 0), 10))
+}
+
+func ParseHeader(header http.Header) (time.Duration, bool) {
+	value := header.Get(BudgetHeader)
+	if value == "" {
+		return 0, false
+	}
+
+	milliseconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || milliseconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond, true
+}
+
+// Middleware applies the budget from the incoming header to the request
+// context and answers 504 without calling next when the budget is
+// smaller than minimum.
+func Middleware(minimum time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remaining, ok := ParseHeader(r.Header)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if remaining < minimum {
+				http.Error(w, insufficient(remaining, minimum).Error(), http.StatusGatewayTimeout)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), remaining)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Transport sends the remaining budget of the request context.
+// Requests whose budget is already smaller than Minimum fail
+// without reaching the network.
+type Transport struct {
+	Base    http.RoundTripper
+	Minimum time.Duration
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	remaining, ok := Remaining(r.Context())
+	if !ok {
+		return base.RoundTrip(r)
+	}
+
+	if err := Require(r.Context(), t.Minimum); err != nil {
+		if r.Body != nil {
+			_ = r.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	clone := r.Clone(r.Context())
+	SetHeader(clone.Header, remaining)
+	return base.RoundTrip(clone)
+}
@@ -4,19 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"golang_course/lessons/contexts/tracing"
 )
 
 func main() {
 	helloWorldHandler := http.HandlerFunc(handle)
-	http.Handle("/welcome", injectTraceID(helloWorldHandler))
+	http.Handle("/welcome", tracing.Middleware(helloWorldHandler))
 	_ = http.ListenAndServe(":8080", nil)
 }
 
 func handle(_ http.ResponseWriter, r *http.Request) {
-	value, ok := r.Context().Value("trace_id").(string)
-	if ok {
-		fmt.Println(value)
-	}
+	fmt.Println(tracing.TraceIDFromContext(r.Context()))
 
 	makeRequest(r.Context())
 }
@@ -24,11 +23,3 @@ func handle(_ http.ResponseWriter, r *http.Request) {
 func makeRequest(_ context.Context) {
 	// requesting to database with context
 }
-
-func injectTraceID(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.WithValue(r.Context(), "trace_id", "12-21-33")
-		req := r.WithContext(ctx)
-		next.ServeHTTP(w, req)
-	})
-}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Context is the standard interface, so contexts from this file and from
+// the standard library can be mixed in one tree. The one limitation is the
+// cause: the standard library can't read it from custom contexts, so a
+// standard child of a custom WithCancelCause parent, and everything below
+// it, reports Canceled instead of the parent's cause.
+type Context = context.Context
+
+type CancelFunc = context.CancelFunc
+
+type CancelCauseFunc = context.CancelCauseFunc
+
+var (
+	Canceled         = context.Canceled
+	DeadlineExceeded = context.DeadlineExceeded
+)
+
+type emptyCtx struct {
+	name string
+}
+
+func (emptyCtx) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (emptyCtx) Done() <-chan struct{}       { return nil }
+func (emptyCtx) Err() error                  { return nil }
+func (emptyCtx) Value(any) any               { return nil }
+func (c emptyCtx) String() string            { return c.name }
+
+var (
+	background = emptyCtx{name: "custom.Background"}
+	todo       = emptyCtx{name: "custom.TODO"}
+)
+
+func Background() Context {
+	return background
+}
+
+func TODO() Context {
+	return todo
+}
+
+// cancelCtxKey is used by Value to find the nearest cancelCtx,
+// so children can register in it instead of starting a goroutine.
+var cancelCtxKey int
+
+type canceler interface {
+	cancel(removeFromParent bool, err, cause error)
+}
+
+type cancelCtx struct {
+	Context
+
+	mutex    sync.Mutex
+	done     chan struct{}
+	children map[canceler]struct{}
+	err      error
+	cause    error
+
+	// stop detaches the context from a parent that isn't a cancelCtx.
+	stop func() bool
+}
+
+func newCancelCtx(parent Context) *cancelCtx {
+	checkParent(parent)
+	return &cancelCtx{
+		Context: parent,
+		done:    make(chan struct{}),
+	}
+}
+
+func checkParent(parent Context) {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+}
+
+func WithCancel(parent Context) (Context, CancelFunc) {
+	c := newCancelCtx(parent)
+	c.propagateCancel(parent, c)
+	return c, func() { c.cancel(true, Canceled, nil) }
+}
+
+func WithCancelCause(parent Context) (Context, CancelCauseFunc) {
+	c := newCancelCtx(parent)
+	c.propagateCancel(parent, c)
+	return c, func(cause error) { c.cancel(true, Canceled, cause) }
+}
+
+func (c *cancelCtx) Value(key any) any {
+	if key == &cancelCtxKey {
+		return c
+	}
+
+	return c.Context.Value(key)
+}
+
+func (c *cancelCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *cancelCtx) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.err
+}
+
+// AfterFunc lets context.AfterFunc and standard library children
+// subscribe to the cancellation without a goroutine.
+func (c *cancelCtx) AfterFunc(f func()) func() bool {
+	a := &afterFunc{f: f}
+
+	c.mutex.Lock()
+	if c.err != nil {
+		c.mutex.Unlock()
+		a.cancel(false, nil, nil)
+		return func() bool { return false }
+	}
+
+	if c.children == nil {
+		c.children = make(map[canceler]struct{})
+	}
+
+	c.children[a] = struct{}{}
+	c.mutex.Unlock()
+
+	return func() bool {
+		stopped := false
+		a.once.Do(func() {
+			stopped = true
+		})
+
+		if stopped {
+			c.mutex.Lock()
+			delete(c.children, a)
+			c.mutex.Unlock()
+		}
+
+		return stopped
+	}
+}
+
+func (c *cancelCtx) propagateCancel(parent Context, child canceler) {
+	done := parent.Done()
+	if done == nil {
+		return
+	}
+
+	select {
+	case <-done:
+		child.cancel(false, parent.Err(), Cause(parent))
+		return
+	default:
+	}
+
+	if p, ok := parentCancelCtx(parent); ok {
+		p.mutex.Lock()
+		if p.err != nil {
+			err, cause := p.err, p.cause
+			p.mutex.Unlock()
+			child.cancel(false, err, cause)
+			return
+		}
+
+		if p.children == nil {
+			p.children = make(map[canceler]struct{})
+		}
+
+		p.children[child] = struct{}{}
+		p.mutex.Unlock()
+		return
+	}
+
+	// Foreign parents are handled by context.AfterFunc: it doesn't need
+	// a goroutine for standard library contexts and for contexts with
+	// an AfterFunc method, and falls back to a goroutine otherwise.
+	stop := context.AfterFunc(parent, func() {
+		child.cancel(false, parent.Err(), Cause(parent))
+	})
+
+	c.mutex.Lock()
+	c.stop = stop
+	c.mutex.Unlock()
+}
+
+func (c *cancelCtx) cancel(removeFromParent bool, err, cause error) {
+	if cause == nil {
+		cause = err
+	}
+
+	c.mutex.Lock()
+	if c.err != nil {
+		c.mutex.Unlock()
+		return
+	}
+
+	c.err = err
+	c.cause = cause
+	close(c.done)
+
+	children := c.children
+	c.children = nil
+	c.mutex.Unlock()
+
+	for child := range children {
+		child.cancel(false, err, cause)
+	}
+
+	if removeFromParent {
+		c.detach(c.Context, c)
+	}
+}
+
+func (c *cancelCtx) detach(parent Context, child canceler) {
+	c.mutex.Lock()
+	stop := c.stop
+	c.mutex.Unlock()
+
+	if stop != nil {
+		stop()
+		return
+	}
+
+	if p, ok := parentCancelCtx(parent); ok {
+		p.mutex.Lock()
+		delete(p.children, child)
+		p.mutex.Unlock()
+	}
+}
+
+// parentCancelCtx returns the nearest *cancelCtx of parent, but only if
+// parent's Done channel belongs to it. A wrapper with its own Done
+// channel must be treated as a foreign context.
+func parentCancelCtx(parent Context) (*cancelCtx, bool) {
+	done := parent.Done()
+	if done == nil {
+		return nil, false
+	}
+
+	p, ok := parent.Value(&cancelCtxKey).(*cancelCtx)
+	if !ok || p.done != done {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// Cause returns the error passed to the CancelCauseFunc, or Err when
+// the context was canceled without a cause. Standard library contexts
+// are supported as well.
+func Cause(c Context) error {
+	if c.Err() == nil {
+		return nil
+	}
+
+	if cc, ok := c.Value(&cancelCtxKey).(*cancelCtx); ok && cc.done == c.Done() {
+		cc.mutex.Lock()
+		defer cc.mutex.Unlock()
+		return cc.cause
+	}
+
+	return context.Cause(c)
+}
+
+type afterFunc struct {
+	once sync.Once
+	f    func()
+}
+
+func (a *afterFunc) cancel(bool, error, error) {
+	a.once.Do(func() {
+		go a.f()
+	})
+}
+
+type timerCtx struct {
+	cancelCtx
+
+	timer    *time.Timer
+	deadline time.Time
+}
+
+func WithDeadline(parent Context, deadline time.Time) (Context, CancelFunc) {
+	return WithDeadlineCause(parent, deadline, nil)
+}
+
+func WithDeadlineCause(parent Context, deadline time.Time, cause error) (Context, CancelFunc) {
+	checkParent(parent)
+	if current, ok := parent.Deadline(); ok && current.Before(deadline) {
+		return WithCancel(parent)
+	}
+
+	c := &timerCtx{
+		cancelCtx: cancelCtx{
+			Context: parent,
+			done:    make(chan struct{}),
+		},
+		deadline: deadline,
+	}
+
+	c.propagateCancel(parent, c)
+
+	duration := time.Until(deadline)
+	if duration <= 0 {
+		c.cancel(true, DeadlineExceeded, cause)
+		return c, func() { c.cancel(false, Canceled, nil) }
+	}
+
+	c.mutex.Lock()
+	if c.err == nil {
+		c.timer = time.AfterFunc(duration, func() {
+			c.cancel(true, DeadlineExceeded, cause)
+		})
+	}
+	c.mutex.Unlock()
+
+	return c, func() { c.cancel(true, Canceled, nil) }
+}
+
+func WithTimeout(parent Context, timeout time.Duration) (Context, CancelFunc) {
+	return WithDeadline(parent, time.Now().Add(timeout))
+}
+
+func WithTimeoutCause(parent Context, timeout time.Duration, cause error) (Context, CancelFunc) {
+	return WithDeadlineCause(parent, time.Now().Add(timeout), cause)
+}
+
+func (c *timerCtx) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *timerCtx) cancel(removeFromParent bool, err, cause error) {
+	c.cancelCtx.cancel(false, err, cause)
+	if removeFromParent {
+		c.detach(c.cancelCtx.Context, c)
+	}
+
+	c.mutex.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mutex.Unlock()
+}
+
+type valueCtx struct {
+	Context
+
+	key   any
+	value any
+}
+
+func WithValue(parent Context, key, value any) Context {
+	checkParent(parent)
+	if key == nil {
+		panic("nil key")
+	}
+
+	if !reflect.TypeOf(key).Comparable() {
+		panic("key is not comparable")
+	}
+
+	return &valueCtx{
+		Context: parent,
+		key:     key,
+		value:   value,
+	}
+}
+
+func (c *valueCtx) Value(key any) any {
+	if c.key == key {
+		return c.value
+	}
+
+	return c.Context.Value(key)
+}
+
+type withoutCancelCtx struct {
+	parent Context
+}
+
+// WithoutCancel keeps the values of parent but isn't canceled with it.
+func WithoutCancel(parent Context) Context {
+	checkParent(parent)
+	return withoutCancelCtx{parent: parent}
+}
+
+func (withoutCancelCtx) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (withoutCancelCtx) Done() <-chan struct{}       { return nil }
+func (withoutCancelCtx) Err() error                  { return nil }
+
+func (c withoutCancelCtx) Value(key any) any {
+	if key == &cancelCtxKey {
+		return nil
+	}
+
+	return c.parent.Value(key)
+}
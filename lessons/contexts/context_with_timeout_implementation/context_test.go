@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+type implementation struct {
+	name             string
+	background       func() Context
+	withCancel       func(Context) (Context, CancelFunc)
+	withCancelCause  func(Context) (Context, CancelCauseFunc)
+	withDeadline     func(Context, time.Time) (Context, CancelFunc)
+	withTimeout      func(Context, time.Duration) (Context, CancelFunc)
+	withTimeoutCause func(Context, time.Duration, error) (Context, CancelFunc)
+	withValue        func(Context, any, any) Context
+	withoutCancel    func(Context) Context
+	cause            func(Context) error
+}
+
+var implementations = []implementation{
+	{
+		name:             "standard",
+		background:       context.Background,
+		withCancel:       context.WithCancel,
+		withCancelCause:  context.WithCancelCause,
+		withDeadline:     context.WithDeadline,
+		withTimeout:      context.WithTimeout,
+		withTimeoutCause: context.WithTimeoutCause,
+		withValue:        context.WithValue,
+		withoutCancel:    context.WithoutCancel,
+		cause:            context.Cause,
+	},
+	{
+		name:             "custom",
+		background:       Background,
+		withCancel:       WithCancel,
+		withCancelCause:  WithCancelCause,
+		withDeadline:     WithDeadline,
+		withTimeout:      WithTimeout,
+		withTimeoutCause: WithTimeoutCause,
+		withValue:        WithValue,
+		withoutCancel:    WithoutCancel,
+		cause:            Cause,
+	},
+}
+
+type key string
+
+func runConformance(t *testing.T, scenario func(t *testing.T, impl implementation)) {
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			scenario(t, impl)
+		})
+	}
+}
+
+func isDone(ctx Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func waitDone(t *testing.T, ctx Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context wasn't canceled")
+	}
+}
+
+func TestBackground(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		ctx := impl.background()
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		assert.Nil(t, ctx.Done())
+		assert.NoError(t, ctx.Err())
+		assert.Nil(t, ctx.Value("key"))
+	})
+}
+
+func TestCancelPropagatesToChildren(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		parent, cancelParent := impl.withCancel(impl.background())
+		child, cancelChild := impl.withCancel(parent)
+		defer cancelChild()
+		grandchild := impl.withValue(child, key("key"), "value")
+
+		assert.False(t, isDone(parent))
+		assert.False(t, isDone(child))
+
+		cancelParent()
+
+		assert.True(t, isDone(parent))
+		assert.True(t, isDone(child))
+		assert.True(t, isDone(grandchild))
+		assert.ErrorIs(t, child.Err(), context.Canceled)
+		assert.ErrorIs(t, grandchild.Err(), context.Canceled)
+	})
+}
+
+func TestCancelDoesNotPropagateToParent(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		parent, cancelParent := impl.withCancel(impl.background())
+		defer cancelParent()
+		child, cancelChild := impl.withCancel(parent)
+		sibling, cancelSibling := impl.withCancel(parent)
+		defer cancelSibling()
+
+		cancelChild()
+		cancelChild()
+
+		assert.True(t, isDone(child))
+		assert.False(t, isDone(parent))
+		assert.False(t, isDone(sibling))
+		assert.NoError(t, parent.Err())
+	})
+}
+
+func TestChildOfCanceledParent(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		parent, cancelParent := impl.withCancelCause(impl.background())
+		cause := errors.New("shutdown")
+		cancelParent(cause)
+
+		child, cancelChild := impl.withCancel(parent)
+		defer cancelChild()
+
+		assert.True(t, isDone(child))
+		assert.ErrorIs(t, child.Err(), context.Canceled)
+		assert.ErrorIs(t, impl.cause(child), cause)
+	})
+}
+
+func TestCancelCause(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		ctx, cancel := impl.withCancelCause(impl.background())
+		child := impl.withValue(ctx, key("key"), "value")
+		assert.NoError(t, impl.cause(ctx))
+
+		cause := errors.New("cause")
+		cancel(cause)
+		cancel(errors.New("ignored"))
+
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+		assert.ErrorIs(t, impl.cause(ctx), cause)
+		assert.ErrorIs(t, impl.cause(child), cause)
+
+		withoutCause, cancelWithoutCause := impl.withCancelCause(impl.background())
+		cancelWithoutCause(nil)
+		assert.ErrorIs(t, impl.cause(withoutCause), context.Canceled)
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		ctx, cancel := impl.withTimeout(impl.background(), 20*time.Millisecond)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(20*time.Millisecond), deadline, 20*time.Millisecond)
+
+		waitDone(t, ctx)
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		assert.ErrorIs(t, impl.cause(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestTimeoutCause(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		cause := errors.New("too slow")
+		ctx, cancel := impl.withTimeoutCause(impl.background(), 10*time.Millisecond, cause)
+		defer cancel()
+
+		waitDone(t, ctx)
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		assert.ErrorIs(t, impl.cause(ctx), cause)
+	})
+}
+
+func TestTimeoutCanceledBeforeDeadline(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		ctx, cancel := impl.withTimeout(impl.background(), time.Hour)
+		cancel()
+
+		assert.True(t, isDone(ctx))
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+}
+
+func TestDeadlineInThePast(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		ctx, cancel := impl.withDeadline(impl.background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		assert.True(t, isDone(ctx))
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+}
+
+func TestChildDeadlineIsLimitedByParent(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		parent, cancelParent := impl.withTimeout(impl.background(), 20*time.Millisecond)
+		defer cancelParent()
+		child, cancelChild := impl.withTimeout(parent, time.Hour)
+		defer cancelChild()
+
+		parentDeadline, _ := parent.Deadline()
+		childDeadline, ok := child.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, parentDeadline, childDeadline)
+
+		waitDone(t, child)
+		assert.ErrorIs(t, child.Err(), context.DeadlineExceeded)
+	})
+}
+
+func TestValue(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		ctx := impl.withValue(impl.background(), key("first"), 1)
+		ctx = impl.withValue(ctx, key("second"), 2)
+		ctx, cancel := impl.withCancel(ctx)
+		defer cancel()
+		ctx = impl.withValue(ctx, key("first"), 3)
+
+		assert.Equal(t, 3, ctx.Value(key("first")))
+		assert.Equal(t, 2, ctx.Value(key("second")))
+		assert.Nil(t, ctx.Value(key("third")))
+		assert.Nil(t, ctx.Value("first"))
+
+		assert.Panics(t, func() { impl.withValue(impl.background(), nil, 1) })
+		assert.Panics(t, func() { impl.withValue(impl.background(), []int{}, 1) })
+		assert.Panics(t, func() { impl.withValue(nil, key("key"), 1) })
+	})
+}
+
+func TestWithoutCancel(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		parent, cancel := impl.withTimeout(impl.withValue(impl.background(), key("key"), "value"), time.Hour)
+		ctx := impl.withoutCancel(parent)
+		cancel()
+
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		assert.Nil(t, ctx.Done())
+		assert.NoError(t, ctx.Err())
+		assert.NoError(t, impl.cause(ctx))
+		assert.Equal(t, "value", ctx.Value(key("key")))
+
+		child, cancelChild := impl.withCancel(ctx)
+		defer cancelChild()
+		assert.False(t, isDone(child))
+	})
+}
+
+func TestAfterFunc(t *testing.T) {
+	runConformance(t, func(t *testing.T, impl implementation) {
+		ctx, cancel := impl.withCancel(impl.background())
+
+		called := make(chan struct{})
+		context.AfterFunc(ctx, func() { close(called) })
+
+		stopped := context.AfterFunc(ctx, func() { t.Error("stopped function was called") })
+		assert.True(t, stopped())
+
+		cancel()
+		<-called
+		assert.False(t, stopped())
+	})
+}
+
+func TestMixedWithStandardLibrary(t *testing.T) {
+	stdParent, cancelStdParent := context.WithCancelCause(context.Background())
+	customChild, cancelCustomChild := WithCancel(stdParent)
+	defer cancelCustomChild()
+	stdGrandchild, cancelStdGrandchild := context.WithTimeout(customChild, time.Hour)
+	defer cancelStdGrandchild()
+
+	cause := errors.New("cause")
+	cancelStdParent(cause)
+
+	waitDone(t, customChild)
+	waitDone(t, stdGrandchild)
+	assert.ErrorIs(t, customChild.Err(), context.Canceled)
+	assert.ErrorIs(t, Cause(customChild), cause)
+	assert.ErrorIs(t, context.Cause(stdGrandchild), cause)
+}
+
+// The standard library reads the cause only from its own contexts, so a
+// standard child of a custom parent gets Canceled instead of the cause.
+func TestStandardChildLosesCustomCause(t *testing.T) {
+	customParent, cancelCustomParent := WithCancelCause(Background())
+	stdChild, cancelStdChild := context.WithCancel(customParent)
+	defer cancelStdChild()
+	customGrandchild, cancelCustomGrandchild := WithCancel(stdChild)
+	defer cancelCustomGrandchild()
+
+	cause := errors.New("cause")
+	cancelCustomParent(cause)
+
+	waitDone(t, stdChild)
+	waitDone(t, customGrandchild)
+	assert.ErrorIs(t, Cause(customParent), cause)
+	assert.ErrorIs(t, context.Cause(stdChild), context.Canceled)
+	assert.ErrorIs(t, Cause(stdChild), context.Canceled)
+	assert.ErrorIs(t, Cause(customGrandchild), context.Canceled)
+}
+
+func TestPropagationWithoutGoroutines(t *testing.T) {
+	const contextsNumber = 1000
+
+	stdParent, cancelStdParent := context.WithCancel(context.Background())
+	defer cancelStdParent()
+	root, cancelRoot := WithCancel(stdParent)
+	defer cancelRoot()
+
+	before := runtime.NumGoroutine()
+
+	cancels := make([]CancelFunc, 0, contextsNumber*2)
+	contexts := make([]Context, 0, contextsNumber*2)
+	for i := 0; i < contextsNumber; i++ {
+		ctx, cancel := WithTimeout(WithValue(root, key("key"), i), time.Hour)
+		cancels = append(cancels, cancel)
+		contexts = append(contexts, ctx)
+
+		stdChild, stdCancel := context.WithCancel(ctx)
+		cancels = append(cancels, stdCancel)
+		contexts = append(contexts, stdChild)
+	}
+
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	cancelStdParent()
+	for _, ctx := range contexts {
+		waitDone(t, ctx)
+	}
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+func TestCancelRemovesChildFromParent(t *testing.T) {
+	parent, cancelParent := WithCancel(Background())
+	defer cancelParent()
+
+	for i := 0; i < 100; i++ {
+		_, cancel := WithTimeout(parent, time.Hour)
+		cancel()
+	}
+
+	cc, ok := parent.Value(&cancelCtxKey).(*cancelCtx)
+	require.True(t, ok)
+
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	assert.Empty(t, cc.children)
+}
@@ -0,0 +1,63 @@
+package ctxkey
+
+import (
+	"context"
+	"fmt"
+)
+
+type Entry struct {
+	key   any
+	value any
+}
+
+func Pair[T any](key *Key[T], value T) Entry {
+	return Entry{key: key, value: value}
+}
+
+type bagCtx struct {
+	context.Context
+	values map[any]any
+}
+
+// WithBag attaches several values in one context layer. Lookups of these
+// keys cost one map access instead of a walk over a WithValue chain.
+// A bag created right on top of another bag absorbs its values,
+// so stacked bags don't make the chain longer either.
+func WithBag(ctx context.Context, entries ...Entry) context.Context {
+	parent := ctx
+	size := len(entries)
+
+	inherited, ok := ctx.(*bagCtx)
+	if ok {
+		parent = inherited.Context
+		size += len(inherited.values)
+	}
+
+	values := make(map[any]any, size)
+	if ok {
+		for key, value := range inherited.values {
+			values[key] = value
+		}
+	}
+
+	for _, entry := range entries {
+		values[entry.key] = entry.value
+	}
+
+	return &bagCtx{
+		Context: parent,
+		values:  values,
+	}
+}
+
+func (c *bagCtx) Value(key any) any {
+	if value, ok := c.values[key]; ok {
+		return value
+	}
+
+	return c.Context.Value(key)
+}
+
+func (c *bagCtx) String() string {
+	return fmt.Sprintf("%v.WithBag(%d values)", c.Context, len(c.values))
+}
@@ -0,0 +1,125 @@
+package ctxkey
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v .
+// go test -bench=. -benchmem .
+
+type user struct {
+	name string
+}
+
+func TestKey(t *testing.T) {
+	userKey := NewKey[*user]("user")
+	otherUserKey := NewKey[*user]("user")
+	requestIDKey := NewKey[string]("request_id")
+
+	ctx := WithValue(context.Background(), userKey, &user{name: "Alice"})
+	ctx = WithValue(ctx, requestIDKey, "12-21-33")
+
+	value, ok := userKey.Value(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "Alice", value.name)
+
+	_, ok = otherUserKey.Value(ctx)
+	assert.False(t, ok)
+
+	requestID, ok := requestIDKey.Value(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "12-21-33", requestID)
+
+	assert.Nil(t, ctx.Value("request_id"))
+	assert.Equal(t, "none", NewKey[string]("missing").ValueOr(ctx, "none"))
+	assert.Equal(t, "request_id", requestIDKey.String())
+}
+
+func TestKeyShadowing(t *testing.T) {
+	key := NewKey[int]("number")
+
+	ctx := WithValue(context.Background(), key, 1)
+	ctx = WithValue(ctx, key, 2)
+
+	value, ok := key.Value(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+}
+
+func TestBag(t *testing.T) {
+	userKey := NewKey[*user]("user")
+	requestIDKey := NewKey[string]("request_id")
+	attemptKey := NewKey[int]("attempt")
+	parentKey := NewKey[string]("parent")
+
+	parent := WithValue(context.Background(), parentKey, "parent")
+	ctx := WithBag(parent,
+		Pair(userKey, &user{name: "Bob"}),
+		Pair(requestIDKey, "22-22-22"),
+	)
+
+	value, ok := userKey.Value(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "Bob", value.name)
+	assert.Equal(t, "22-22-22", requestIDKey.ValueOr(ctx, ""))
+	assert.Equal(t, "parent", parentKey.ValueOr(ctx, ""))
+
+	_, ok = attemptKey.Value(ctx)
+	assert.False(t, ok)
+
+	child := WithBag(ctx, Pair(attemptKey, 2), Pair(requestIDKey, "33-33-33"))
+	assert.Equal(t, 2, attemptKey.ValueOr(child, 0))
+	assert.Equal(t, "33-33-33", requestIDKey.ValueOr(child, ""))
+	assert.Equal(t, "Bob", userKey.ValueOr(child, nil).name)
+	assert.Equal(t, "22-22-22", requestIDKey.ValueOr(ctx, ""))
+
+	bag := child.(*bagCtx)
+	assert.Len(t, bag.values, 3)
+	assert.Equal(t, parent, bag.Context)
+}
+
+func TestBagDoesNotHideNewerValues(t *testing.T) {
+	key := NewKey[string]("key")
+
+	ctx := WithBag(context.Background(), Pair(key, "bag"))
+	ctx = WithValue(ctx, key, "value")
+	ctx = WithBag(ctx, Pair(NewKey[int]("other"), 1))
+
+	assert.Equal(t, "value", key.ValueOr(ctx, ""))
+}
+
+const chainDepth = 32
+
+func BenchmarkWithValueChain(b *testing.B) {
+	keys := make([]*Key[int], chainDepth)
+	ctx := context.Background()
+	for idx := range keys {
+		keys[idx] = NewKey[int](fmt.Sprint(idx))
+		ctx = WithValue(ctx, keys[idx], idx)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = keys[0].Value(ctx)
+	}
+}
+
+func BenchmarkBag(b *testing.B) {
+	keys := make([]*Key[int], chainDepth)
+	entries := make([]Entry, chainDepth)
+	for idx := range keys {
+		keys[idx] = NewKey[int](fmt.Sprint(idx))
+		entries[idx] = Pair(keys[idx], idx)
+	}
+
+	ctx := WithBag(context.Background(), entries...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = keys[0].Value(ctx)
+	}
+}
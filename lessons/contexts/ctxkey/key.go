@@ -0,0 +1,37 @@
+package ctxkey
+
+import (
+	"context"
+)
+
+// Key is a typed context key. Keys are compared by pointer, so two keys
+// with the same name and type never collide, and values come back
+// with their type without assertions at call sites.
+type Key[T any] struct {
+	name string
+}
+
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+func WithValue[T any](ctx context.Context, key *Key[T], value T) context.Context {
+	return context.WithValue(ctx, key, value)
+}
+
+func (k *Key[T]) Value(ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(k).(T)
+	return value, ok
+}
+
+func (k *Key[T]) ValueOr(ctx context.Context, fallback T) T {
+	if value, ok := k.Value(ctx); ok {
+		return value
+	}
+
+	return fallback
+}
+
+func (k *Key[T]) String() string {
+	return k.name
+}
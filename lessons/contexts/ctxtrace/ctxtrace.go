@@ -0,0 +1,204 @@
+package ctxtrace
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang_course/lessons/contexts/ctxkey"
+)
+
+// Tracing is off by default: the constructors below then return the
+// standard library contexts as is, without wrappers, stacks or
+// bookkeeping. Only contexts created while tracing is on are tracked.
+var enabled atomic.Bool
+
+func Enable()       { enabled.Store(true) }
+func Disable()      { enabled.Store(false) }
+func Enabled() bool { return enabled.Load() }
+
+type Reason int
+
+const (
+	// ReasonCanceled means the cancel function of the context was called.
+	ReasonCanceled Reason = iota + 1
+	// ReasonDeadline means the own deadline of the context was exceeded.
+	ReasonDeadline
+	// ReasonParent means the cancellation came from the parent context.
+	ReasonParent
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonCanceled:
+		return "canceled"
+	case ReasonDeadline:
+		return "deadline exceeded"
+	case ReasonParent:
+		return "parent canceled"
+	default:
+		return "unknown"
+	}
+}
+
+var nodeKey = ctxkey.NewKey[*node]("ctxtrace.node")
+
+type node struct {
+	id       uint64
+	parent   *node
+	ctx      context.Context
+	created  []uintptr
+	deadline time.Time
+
+	mutex   sync.Mutex
+	pending []uintptr
+	record  *Cancellation
+}
+
+var (
+	lastID    atomic.Uint64
+	liveMutex sync.Mutex
+	live      = map[*node]struct{}{}
+)
+
+func WithCancel(parent context.Context) (context.Context, context.CancelFunc) {
+	if !enabled.Load() {
+		return context.WithCancel(parent)
+	}
+
+	ctx, cancel := WithCancelCause(parent)
+	return ctx, func() { cancel(nil) }
+}
+
+func WithCancelCause(parent context.Context) (context.Context, context.CancelCauseFunc) {
+	if !enabled.Load() {
+		return context.WithCancelCause(parent)
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	return track(parent, ctx, func(cause error) { cancel(cause) })
+}
+
+func WithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, time.Now().Add(timeout))
+}
+
+func WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	if !enabled.Load() {
+		return context.WithDeadline(parent, deadline)
+	}
+
+	ctx, cancel := context.WithDeadline(parent, deadline)
+	traced, tracedCancel := track(parent, ctx, func(error) { cancel() })
+	return traced, func() { tracedCancel(nil) }
+}
+
+func track(parent, ctx context.Context, cancel func(error)) (context.Context, context.CancelCauseFunc) {
+	n := &node{
+		id:      lastID.Add(1),
+		ctx:     ctx,
+		created: callers(),
+	}
+
+	n.parent, _ = nodeKey.Value(parent)
+	n.deadline, _ = ctx.Deadline()
+
+	liveMutex.Lock()
+	live[n] = struct{}{}
+	liveMutex.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		n.done(parent)
+	})
+
+	traced := ctxkey.WithValue(ctx, nodeKey, n)
+	return traced, func(cause error) {
+		n.mutex.Lock()
+		if n.record == nil && n.pending == nil && ctx.Err() == nil {
+			n.pending = callers()
+		}
+		n.mutex.Unlock()
+
+		cancel(cause)
+	}
+}
+
+func (n *node) done(parent context.Context) {
+	record := &Cancellation{
+		ID:    n.id,
+		At:    time.Now(),
+		Err:   n.ctx.Err(),
+		Cause: context.Cause(n.ctx),
+	}
+
+	n.mutex.Lock()
+	switch {
+	case n.pending != nil:
+		record.Reason = ReasonCanceled
+		record.stack = n.pending
+	case parent.Err() != nil:
+		record.Reason = ReasonParent
+	default:
+		record.Reason = ReasonDeadline
+	}
+
+	record.created = n.created
+	n.record = record
+	n.mutex.Unlock()
+
+	liveMutex.Lock()
+	delete(live, n)
+	liveMutex.Unlock()
+}
+
+func (n *node) cancellation() (*Cancellation, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.record, n.record != nil
+}
+
+// CancellationOf returns how ctx itself was canceled. ok is false when
+// ctx isn't traced or isn't canceled yet. Cancellation is recorded
+// asynchronously, right after Done is closed.
+func CancellationOf(ctx context.Context) (*Cancellation, bool) {
+	n, ok := nodeKey.Value(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	return n.cancellation()
+}
+
+// Origin walks up from ctx through the contexts canceled by their
+// parents and returns the traced ancestor where the cancellation started.
+func Origin(ctx context.Context) (*Cancellation, bool) {
+	n, ok := nodeKey.Value(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	record, ok := n.cancellation()
+	if !ok {
+		return nil, false
+	}
+
+	for record.Reason == ReasonParent && n.parent != nil {
+		parentRecord, ok := n.parent.cancellation()
+		if !ok {
+			break
+		}
+
+		n, record = n.parent, parentRecord
+	}
+
+	return record, true
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	count := runtime.Callers(2, pcs)
+	return pcs[:count]
+}
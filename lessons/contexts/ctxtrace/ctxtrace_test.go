@@ -0,0 +1,192 @@
+package ctxtrace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+func enableTracing(t *testing.T) {
+	Enable()
+	t.Cleanup(Disable)
+}
+
+func waitCancellation(t *testing.T, ctx context.Context) *Cancellation {
+	<-ctx.Done()
+	for i := 0; i < 1000; i++ {
+		if record, ok := CancellationOf(ctx); ok {
+			return record
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("cancellation wasn't recorded")
+	return nil
+}
+
+func TestDisabledByDefault(t *testing.T) {
+	require.False(t, Enabled())
+
+	ctx, cancel := WithCancel(context.Background())
+	defer cancel()
+	stdCtx, stdCancel := context.WithCancel(context.Background())
+	defer stdCancel()
+
+	assert.Equal(t, fmt.Sprintf("%T", stdCtx), fmt.Sprintf("%T", ctx))
+
+	cancel()
+	_, ok := CancellationOf(ctx)
+	assert.False(t, ok)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, cancel := WithTimeout(context.Background(), time.Hour)
+		cancel()
+	})
+	stdAllocs := testing.AllocsPerRun(100, func() {
+		_, cancel := context.WithTimeout(context.Background(), time.Hour)
+		cancel()
+	})
+	assert.Equal(t, stdAllocs, allocs)
+}
+
+func cancelFromHelper(cancel context.CancelCauseFunc) {
+	cancel(errors.New("user left"))
+}
+
+func TestOriginOfExplicitCancel(t *testing.T) {
+	enableTracing(t)
+
+	root, cancelRoot := WithCancelCause(context.Background())
+	middle, cancelMiddle := WithCancel(context.WithValue(root, "key", "value"))
+	defer cancelMiddle()
+	leaf, cancelLeaf := WithTimeout(middle, time.Hour)
+	defer cancelLeaf()
+
+	cancelFromHelper(cancelRoot)
+
+	leafRecord := waitCancellation(t, leaf)
+	assert.Equal(t, ReasonParent, leafRecord.Reason)
+	assert.Empty(t, leafRecord.Stack())
+
+	waitCancellation(t, middle)
+	waitCancellation(t, root)
+
+	origin, ok := Origin(leaf)
+	require.True(t, ok)
+	assert.Equal(t, ReasonCanceled, origin.Reason)
+	assert.EqualError(t, origin.Cause, "user left")
+	assert.ErrorIs(t, origin.Err, context.Canceled)
+	assert.True(t, strings.HasPrefix(origin.Stack(), "golang_course/lessons/contexts/ctxtrace.cancelFromHelper"), origin.Stack())
+	assert.Contains(t, origin.Created(), "TestOriginOfExplicitCancel")
+	assert.Contains(t, origin.String(), "canceled at:")
+}
+
+func TestOriginOfDeadline(t *testing.T) {
+	enableTracing(t)
+
+	parent, cancelParent := WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelParent()
+	child, cancelChild := WithCancel(parent)
+	defer cancelChild()
+
+	waitCancellation(t, parent)
+	waitCancellation(t, child)
+
+	origin, ok := Origin(child)
+	require.True(t, ok)
+	assert.Equal(t, ReasonDeadline, origin.Reason)
+	assert.ErrorIs(t, origin.Err, context.DeadlineExceeded)
+
+	record, _ := CancellationOf(parent)
+	assert.Equal(t, origin.ID, record.ID)
+}
+
+func TestOriginOfChildCanceledFirst(t *testing.T) {
+	enableTracing(t)
+
+	parent, cancelParent := WithCancel(context.Background())
+	child, cancelChild := WithCancel(parent)
+
+	cancelChild()
+	waitCancellation(t, child)
+	cancelParent()
+	waitCancellation(t, parent)
+
+	origin, ok := Origin(child)
+	require.True(t, ok)
+	assert.Equal(t, ReasonCanceled, origin.Reason)
+
+	childRecord, _ := CancellationOf(child)
+	assert.Equal(t, childRecord.ID, origin.ID)
+}
+
+func TestOriginOfUntracedParent(t *testing.T) {
+	enableTracing(t)
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	child, cancelChild := WithCancel(parent)
+	defer cancelChild()
+
+	cancelParent()
+	waitCancellation(t, child)
+
+	origin, ok := Origin(child)
+	require.True(t, ok)
+	assert.Equal(t, ReasonParent, origin.Reason)
+
+	_, ok = Origin(parent)
+	assert.False(t, ok)
+}
+
+func TestDump(t *testing.T) {
+	enableTracing(t)
+
+	root, cancelRoot := WithCancel(context.Background())
+	defer cancelRoot()
+	first, cancelFirst := WithTimeout(root, time.Hour)
+	defer cancelFirst()
+	second, cancelSecond := WithCancel(first)
+	defer cancelSecond()
+	canceled, cancelCanceled := WithCancel(root)
+	cancelCanceled()
+	waitCancellation(t, canceled)
+
+	var buffer bytes.Buffer
+	require.NoError(t, Dump(&buffer))
+
+	rootNode, _ := nodeKey.Value(root)
+	firstNode, _ := nodeKey.Value(first)
+	secondNode, _ := nodeKey.Value(second)
+	canceledNode, _ := nodeKey.Value(canceled)
+
+	dump := buffer.String()
+	assert.Contains(t, dump, fmt.Sprintf("#%d created at golang_course/lessons/contexts/ctxtrace.TestDump", rootNode.id))
+	assert.Contains(t, dump, fmt.Sprintf("\n  #%d created at", firstNode.id))
+	assert.Contains(t, dump, fmt.Sprintf("\n    #%d created at", secondNode.id))
+	assert.Contains(t, dump, "deadline in 59m59")
+	assert.NotContains(t, dump, fmt.Sprintf("#%d ", canceledNode.id))
+}
+
+func BenchmarkDisabledWithCancel(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, cancel := WithCancel(context.Background())
+		cancel()
+	}
+}
+
+func BenchmarkStandardWithCancel(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, cancel := context.WithCancel(context.Background())
+		cancel()
+	}
+}
@@ -0,0 +1,148 @@
+package ctxtrace
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Cancellation struct {
+	ID     uint64
+	Reason Reason
+	At     time.Time
+	Err    error
+	Cause  error
+
+	stack   []uintptr
+	created []uintptr
+}
+
+// Stack returns where the cancel function was called,
+// it is empty for other reasons.
+func (c *Cancellation) Stack() string {
+	return formatStack(c.stack)
+}
+
+// Created returns where the canceled context was created.
+func (c *Cancellation) Created() string {
+	return formatStack(c.created)
+}
+
+func (c *Cancellation) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "context #%d: %s: %v", c.ID, c.Reason, c.Cause)
+	if len(c.stack) != 0 {
+		builder.WriteString("\ncanceled at:\n")
+		builder.WriteString(c.Stack())
+	}
+
+	builder.WriteString("\ncreated at:\n")
+	builder.WriteString(c.Created())
+	return builder.String()
+}
+
+// Dump writes the tree of traced contexts that aren't canceled yet.
+func Dump(w io.Writer) error {
+	liveMutex.Lock()
+	nodes := make([]*node, 0, len(live))
+	for n := range live {
+		nodes = append(nodes, n)
+	}
+	liveMutex.Unlock()
+
+	isLive := make(map[*node]bool, len(nodes))
+	for _, n := range nodes {
+		isLive[n] = true
+	}
+
+	children := make(map[*node][]*node)
+	var roots []*node
+	for _, n := range nodes {
+		if n.parent != nil && isLive[n.parent] {
+			children[n.parent] = append(children[n.parent], n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+
+	var dump func(n *node, depth int) error
+	dump = func(n *node, depth int) error {
+		line := fmt.Sprintf("%s#%d created at %s", strings.Repeat("  ", depth), n.id, firstFrame(n.created))
+		if !n.deadline.IsZero() {
+			line += fmt.Sprintf(", deadline in %s", time.Until(n.deadline).Round(time.Millisecond))
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+
+		sortNodes(children[n])
+		for _, child := range children[n] {
+			if err := dump(child, depth+1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	sortNodes(roots)
+	for _, root := range roots {
+		if err := dump(root, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortNodes(nodes []*node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].id < nodes[j].id
+	})
+}
+
+// packageDir is used to hide the frames of this package from stacks,
+// so they start at the code that created or canceled the context.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+func userFrames(pcs []uintptr) []runtime.Frame {
+	var result []runtime.Frame
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		internal := filepath.Dir(frame.File) == packageDir && !strings.HasSuffix(frame.File, "_test.go")
+		if frame.Function != "" && !internal {
+			result = append(result, frame)
+		}
+
+		if !more {
+			return result
+		}
+	}
+}
+
+func firstFrame(pcs []uintptr) string {
+	frames := userFrames(pcs)
+	if len(frames) == 0 {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s (%s:%d)", frames[0].Function, frames[0].File, frames[0].Line)
+}
+
+func formatStack(pcs []uintptr) string {
+	var builder strings.Builder
+	for _, frame := range userFrames(pcs) {
+		fmt.Fprintf(&builder, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+
+	return builder.String()
+}
@@ -6,15 +6,12 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
 	"time"
+
+	"golang_course/lessons/contexts/lifecycle"
 )
 
 func main() {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
-
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = io.WriteString(w, "hello world\n")
 	})
@@ -30,14 +27,17 @@ func main() {
 		}
 	}()
 
-	<-ctx.Done()
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	cancel()
+	manager := lifecycle.NewManager(lifecycle.WithTimeout(5 * time.Second))
+	_ = manager.Register(lifecycle.Hook{
+		Name:    "http_server",
+		Timeout: time.Second,
+		Stop:    server.Shutdown, // gets a context that is canceled only after the timeout
+	})
 
-	if err := server.Shutdown(ctx); err != nil {
+	report, err := manager.Wait(context.Background())
+	if err != nil {
 		log.Print(err.Error())
 	}
 
-	fmt.Println("canceled")
+	fmt.Println("canceled, timed out hooks:", report.TimedOut())
 }
@@ -0,0 +1,238 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrDuplicateHook     = errors.New("duplicate hook")
+	ErrUnknownDependency = errors.New("unknown dependency")
+	ErrDependencyCycle   = errors.New("dependency cycle")
+	ErrForcedExit        = errors.New("forced exit by second signal")
+	ErrNilStop           = errors.New("hook without stop function")
+)
+
+// Hook stops one component. DependsOn lists the components it uses:
+// they were started earlier, so they are stopped after this hook.
+type Hook struct {
+	Name      string
+	DependsOn []string
+	Timeout   time.Duration
+	Stop      func(ctx context.Context) error
+}
+
+type Manager struct {
+	mutex     sync.Mutex
+	hooks     []Hook
+	timeout   time.Duration
+	signals   []os.Signal
+	forceExit func()
+}
+
+type Option func(*Manager)
+
+// WithTimeout sets the global deadline for the whole shutdown.
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.timeout = timeout
+	}
+}
+
+func WithSignals(signals ...os.Signal) Option {
+	return func(m *Manager) {
+		m.signals = signals
+	}
+}
+
+// WithForceExit replaces os.Exit(1) called on the second signal.
+func WithForceExit(forceExit func()) Option {
+	return func(m *Manager) {
+		m.forceExit = forceExit
+	}
+}
+
+func NewManager(options ...Option) *Manager {
+	m := &Manager{
+		timeout:   30 * time.Second,
+		signals:   []os.Signal{os.Interrupt, syscall.SIGTERM},
+		forceExit: func() { os.Exit(1) },
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+func (m *Manager) Register(hook Hook) error {
+	if hook.Stop == nil {
+		return fmt.Errorf("%w: %s", ErrNilStop, hook.Name)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, registered := range m.hooks {
+		if registered.Name == hook.Name {
+			return fmt.Errorf("%w: %s", ErrDuplicateHook, hook.Name)
+		}
+	}
+
+	m.hooks = append(m.hooks, hook)
+	return nil
+}
+
+// Wait blocks until one of the signals arrives or ctx is done and then
+// runs the shutdown. A second signal during the shutdown forces the exit.
+func (m *Manager) Wait(ctx context.Context) (Report, error) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, m.signals...)
+	defer signal.Stop(signals)
+
+	select {
+	case <-signals:
+	case <-ctx.Done():
+	}
+
+	type result struct {
+		report Report
+		err    error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		report, err := m.Shutdown(context.Background())
+		done <- result{report: report, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.report, r.err
+	case <-signals:
+		m.forceExit()
+		return Report{}, ErrForcedExit
+	}
+}
+
+// Shutdown runs the hooks in reverse dependency order. Each hook gets
+// its own deadline limited by the global one, a hook that doesn't return
+// in time is reported as timed out and the shutdown goes on.
+func (m *Manager) Shutdown(ctx context.Context) (Report, error) {
+	m.mutex.Lock()
+	hooks := make([]Hook, len(m.hooks))
+	copy(hooks, m.hooks)
+	m.mutex.Unlock()
+
+	order, err := stopOrder(hooks)
+	if err != nil {
+		return Report{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	report := Report{Results: make([]HookResult, 0, len(order))}
+	for _, hook := range order {
+		report.Results = append(report.Results, runHook(ctx, hook))
+	}
+
+	return report, report.Err()
+}
+
+func runHook(ctx context.Context, hook Hook) HookResult {
+	result := HookResult{Name: hook.Name}
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		result.TimedOut = true
+		return result
+	}
+
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.Stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		result.Err = err
+		result.TimedOut = errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil
+	case <-ctx.Done():
+		result.Err = ctx.Err()
+		result.TimedOut = true
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// stopOrder sorts hooks so that every hook goes before its dependencies.
+// Independent hooks are stopped in reverse registration order.
+func stopOrder(hooks []Hook) ([]Hook, error) {
+	indexes := make(map[string]int, len(hooks))
+	for idx, hook := range hooks {
+		indexes[hook.Name] = idx
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make([]int, len(hooks))
+	startOrder := make([]Hook, 0, len(hooks))
+
+	var visit func(idx int) error
+	visit = func(idx int) error {
+		switch states[idx] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, hooks[idx].Name)
+		}
+
+		states[idx] = visiting
+		for _, dependency := range hooks[idx].DependsOn {
+			dependencyIdx, ok := indexes[dependency]
+			if !ok {
+				return fmt.Errorf("%w: %s depends on %s", ErrUnknownDependency, hooks[idx].Name, dependency)
+			}
+
+			if err := visit(dependencyIdx); err != nil {
+				return err
+			}
+		}
+
+		states[idx] = visited
+		startOrder = append(startOrder, hooks[idx])
+		return nil
+	}
+
+	for idx := range hooks {
+		if err := visit(idx); err != nil {
+			return nil, err
+		}
+	}
+
+	order := make([]Hook, 0, len(startOrder))
+	for idx := len(startOrder) - 1; idx >= 0; idx-- {
+		order = append(order, startOrder[idx])
+	}
+
+	return order, nil
+}
@@ -0,0 +1,246 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+type recorder struct {
+	mutex sync.Mutex
+	names []string
+}
+
+func (r *recorder) hook(name string, dependsOn ...string) Hook {
+	return Hook{
+		Name:      name,
+		DependsOn: dependsOn,
+		Stop: func(context.Context) error {
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			r.names = append(r.names, name)
+			return nil
+		},
+	}
+}
+
+func (r *recorder) stopped() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.names...)
+}
+
+func TestShutdownOrder(t *testing.T) {
+	var r recorder
+	manager := NewManager()
+
+	require.NoError(t, manager.Register(r.hook("database")))
+	require.NoError(t, manager.Register(r.hook("cache")))
+	require.NoError(t, manager.Register(r.hook("server", "database", "queue")))
+	require.NoError(t, manager.Register(r.hook("queue", "database")))
+	require.NoError(t, manager.Register(r.hook("metrics")))
+
+	report, err := manager.Shutdown(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.TimedOut())
+	assert.Equal(t, []string{"metrics", "server", "queue", "cache", "database"}, r.stopped())
+}
+
+func TestRegisterErrors(t *testing.T) {
+	var r recorder
+	manager := NewManager()
+
+	require.NoError(t, manager.Register(r.hook("server")))
+	assert.ErrorIs(t, manager.Register(r.hook("server")), ErrDuplicateHook)
+	assert.ErrorIs(t, manager.Register(Hook{Name: "empty"}), ErrNilStop)
+}
+
+func TestShutdownDependencyErrors(t *testing.T) {
+	var r recorder
+
+	manager := NewManager()
+	require.NoError(t, manager.Register(r.hook("server", "database")))
+	_, err := manager.Shutdown(context.Background())
+	assert.ErrorIs(t, err, ErrUnknownDependency)
+
+	manager = NewManager()
+	require.NoError(t, manager.Register(r.hook("first", "second")))
+	require.NoError(t, manager.Register(r.hook("second", "first")))
+	_, err = manager.Shutdown(context.Background())
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+
+	assert.Empty(t, r.stopped())
+}
+
+func TestHookTimeouts(t *testing.T) {
+	var r recorder
+	manager := NewManager(WithTimeout(time.Second))
+
+	require.NoError(t, manager.Register(r.hook("database")))
+	require.NoError(t, manager.Register(Hook{
+		Name:      "stuck",
+		DependsOn: []string{"database"},
+		Timeout:   20 * time.Millisecond,
+		Stop: func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	}))
+	require.NoError(t, manager.Register(Hook{
+		Name:    "slow",
+		Timeout: 20 * time.Millisecond,
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	expectedErr := errors.New("flush failed")
+	require.NoError(t, manager.Register(Hook{
+		Name: "failing",
+		Stop: func(context.Context) error {
+			return expectedErr
+		},
+	}))
+
+	start := time.Now()
+	report, err := manager.Shutdown(context.Background())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	assert.ErrorIs(t, err, expectedErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"slow", "stuck"}, report.TimedOut())
+	assert.Equal(t, []string{"database"}, r.stopped())
+	require.Len(t, report.Results, 4)
+	assert.Equal(t, "failing", report.Results[0].Name)
+}
+
+func TestGlobalTimeout(t *testing.T) {
+	manager := NewManager(WithTimeout(30 * time.Millisecond))
+
+	var called atomic.Bool
+	require.NoError(t, manager.Register(Hook{
+		Name: "last",
+		Stop: func(context.Context) error {
+			called.Store(true)
+			return nil
+		},
+	}))
+	require.NoError(t, manager.Register(Hook{
+		Name:    "slow",
+		Timeout: time.Hour,
+		Stop: func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(30*time.Millisecond), deadline, 30*time.Millisecond)
+
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+
+	report, err := manager.Shutdown(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"slow", "last"}, report.TimedOut())
+	assert.False(t, called.Load())
+}
+
+func TestWaitForSignal(t *testing.T) {
+	var r recorder
+	manager := NewManager(WithSignals(syscall.SIGUSR1))
+	require.NoError(t, manager.Register(r.hook("server")))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		report, err := manager.Wait(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, report.Results, 1)
+	}()
+
+	sendUntilDone(t, syscall.SIGUSR1, done)
+	assert.Equal(t, []string{"server"}, r.stopped())
+}
+
+func TestSecondSignalForcesExit(t *testing.T) {
+	var forced atomic.Bool
+	release := make(chan struct{})
+	defer close(release)
+
+	manager := NewManager(
+		WithSignals(syscall.SIGUSR2),
+		WithForceExit(func() { forced.Store(true) }),
+	)
+
+	started := make(chan struct{})
+	require.NoError(t, manager.Register(Hook{
+		Name: "stuck",
+		Stop: func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := manager.Wait(context.Background())
+		assert.ErrorIs(t, err, ErrForcedExit)
+	}()
+
+	sendUntilDone(t, syscall.SIGUSR2, started)
+	sendUntilDone(t, syscall.SIGUSR2, done)
+	assert.True(t, forced.Load())
+}
+
+func TestWaitForContext(t *testing.T) {
+	var r recorder
+	manager := NewManager(WithSignals(syscall.SIGUSR1))
+	require.NoError(t, manager.Register(r.hook("server")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := manager.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"server"}, r.stopped())
+}
+
+// sendUntilDone repeats the signal because Wait may not have
+// subscribed to it yet when the first one is sent. The test keeps its
+// own subscription, so the signal never kills the test process.
+func sendUntilDone(t *testing.T, sig syscall.Signal, done <-chan struct{}) {
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, sig)
+	defer signal.Stop(guard)
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		require.NoError(t, process.Signal(sig))
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		case <-timeout:
+			t.Fatal("signal wasn't handled")
+		}
+	}
+}
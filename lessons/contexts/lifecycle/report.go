@@ -0,0 +1,40 @@
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type HookResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+	TimedOut bool
+}
+
+type Report struct {
+	Results []HookResult
+}
+
+func (r Report) TimedOut() []string {
+	var names []string
+	for _, result := range r.Results {
+		if result.TimedOut {
+			names = append(names, result.Name)
+		}
+	}
+
+	return names
+}
+
+func (r Report) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
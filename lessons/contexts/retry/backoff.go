@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the delay before the next attempt. attempt starts
+// from 1 for the first retry, previous is the last returned delay.
+type Backoff interface {
+	Next(attempt int, previous time.Duration) time.Duration
+}
+
+type BackoffFunc func(attempt int, previous time.Duration) time.Duration
+
+func (f BackoffFunc) Next(attempt int, previous time.Duration) time.Duration {
+	return f(attempt, previous)
+}
+
+func Constant(delay time.Duration) Backoff {
+	return BackoffFunc(func(int, time.Duration) time.Duration {
+		return delay
+	})
+}
+
+// Exponential doubles the delay on every attempt up to maximum.
+func Exponential(base, maximum time.Duration) Backoff {
+	return BackoffFunc(func(attempt int, _ time.Duration) time.Duration {
+		return exponential(base, maximum, attempt)
+	})
+}
+
+// ExponentialFullJitter picks a random delay between zero and the
+// exponential one, so clients that failed together don't retry together.
+func ExponentialFullJitter(base, maximum time.Duration) Backoff {
+	return BackoffFunc(func(attempt int, _ time.Duration) time.Duration {
+		return randomBetween(0, exponential(base, maximum, attempt))
+	})
+}
+
+// DecorrelatedJitter picks a random delay between base and three
+// times the previous one, limited by maximum.
+func DecorrelatedJitter(base, maximum time.Duration) Backoff {
+	return BackoffFunc(func(_ int, previous time.Duration) time.Duration {
+		previous = max(previous, base)
+		upper := previous * 3
+		if upper < previous || upper > maximum {
+			upper = maximum
+		}
+
+		return min(randomBetween(base, upper), maximum)
+	})
+}
+
+func exponential(base, maximum time.Duration, attempt int) time.Duration {
+	delay := base
+	for idx := 1; idx < attempt; idx++ {
+		delay *= 2
+		if delay <= 0 || delay >= maximum {
+			return maximum
+		}
+	}
+
+	return min(delay, maximum)
+}
+
+func randomBetween(lower, upper time.Duration) time.Duration {
+	if upper <= lower {
+		return lower
+	}
+
+	return lower + rand.N(upper-lower+1)
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"sync"
+)
+
+// Budget is a token bucket shared by all calls to one dependency,
+// the same scheme gRPC uses for retry throttling. Every failure takes
+// a token, every success returns ratio tokens, and retries are allowed
+// only while more than half of the tokens are left. When the dependency
+// is down the bucket drains and clients stop multiplying its load.
+type Budget struct {
+	mutex     sync.Mutex
+	tokens    float64
+	maxTokens float64
+	ratio     float64
+}
+
+func NewBudget(maxTokens int, ratio float64) *Budget {
+	return &Budget{
+		tokens:    float64(maxTokens),
+		maxTokens: float64(maxTokens),
+		ratio:     ratio,
+	}
+}
+
+func (b *Budget) Tokens() float64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.tokens
+}
+
+func (b *Budget) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.tokens > b.maxTokens/2
+}
+
+func (b *Budget) onSuccess() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+func (b *Budget) onFailure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.tokens = max(b.tokens-1, 0)
+}
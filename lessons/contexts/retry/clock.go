@@ -0,0 +1,17 @@
+package retry
+
+import (
+	"time"
+)
+
+type Clock interface {
+	After(delay time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) After(delay time.Duration) <-chan time.Time {
+	return time.After(delay)
+}
+
+var RealClock Clock = realClock{}
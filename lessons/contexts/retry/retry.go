@@ -0,0 +1,174 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrMaxAttempts     = errors.New("max attempts reached")
+	ErrBudgetExhausted = errors.New("retry budget exhausted")
+	ErrPermanent       = errors.New("permanent error")
+)
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Error is returned when Do gives up. Reason tells why retrying stopped:
+// ErrMaxAttempts, ErrBudgetExhausted, ErrPermanent or the cause of
+// the context cancellation. Last is the error of the last attempt.
+type Error struct {
+	Attempts int
+	Reason   error
+	Last     error
+}
+
+func (e *Error) Error() string {
+	if e.Last == nil {
+		return fmt.Sprintf("retry stopped after %d attempts: %v", e.Attempts, e.Reason)
+	}
+
+	return fmt.Sprintf("retry stopped after %d attempts: %v: %v", e.Attempts, e.Reason, e.Last)
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Last == nil {
+		return []error{e.Reason}
+	}
+
+	return []error{e.Reason, e.Last}
+}
+
+type config struct {
+	attempts  int
+	backoff   Backoff
+	budget    *Budget
+	clock     Clock
+	retryable func(error) bool
+	onRetry   func(attempt int, err error, delay time.Duration)
+}
+
+type Option func(*config)
+
+// WithMaxAttempts limits the number of calls including the first one,
+// zero means no limit.
+func WithMaxAttempts(attempts int) Option {
+	return func(c *config) {
+		c.attempts = attempts
+	}
+}
+
+func WithBackoff(backoff Backoff) Option {
+	return func(c *config) {
+		c.backoff = backoff
+	}
+}
+
+func WithBudget(budget *Budget) Option {
+	return func(c *config) {
+		c.budget = budget
+	}
+}
+
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithRetryIf sets the classifier for errors that aren't marked with
+// Permanent. Use errors.Is and errors.As inside to match wrapped errors.
+func WithRetryIf(retryable func(error) bool) Option {
+	return func(c *config) {
+		c.retryable = retryable
+	}
+}
+
+func WithOnRetry(onRetry func(attempt int, err error, delay time.Duration)) Option {
+	return func(c *config) {
+		c.onRetry = onRetry
+	}
+}
+
+// Do calls op until it succeeds or retrying has to stop.
+func Do(ctx context.Context, op func(context.Context) error, opts ...Option) error {
+	c := config{
+		attempts:  3,
+		backoff:   ExponentialFullJitter(100*time.Millisecond, 10*time.Second),
+		clock:     RealClock,
+		retryable: func(error) bool { return true },
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var delay time.Duration
+	var last error
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return &Error{Attempts: attempt - 1, Reason: context.Cause(ctx), Last: last}
+		}
+
+		err := op(ctx)
+		if err == nil {
+			if c.budget != nil {
+				c.budget.onSuccess()
+			}
+
+			return nil
+		}
+
+		if c.budget != nil {
+			c.budget.onFailure()
+		}
+
+		last = err
+
+		stop := func(reason error) error {
+			return &Error{Attempts: attempt, Reason: reason, Last: err}
+		}
+
+		switch {
+		case IsPermanent(err) || !c.retryable(err):
+			return stop(ErrPermanent)
+		case ctx.Err() != nil:
+			return stop(context.Cause(ctx))
+		case c.attempts > 0 && attempt >= c.attempts:
+			return stop(ErrMaxAttempts)
+		case c.budget != nil && !c.budget.allow():
+			return stop(ErrBudgetExhausted)
+		}
+
+		delay = c.backoff.Next(attempt, delay)
+		if c.onRetry != nil {
+			c.onRetry(attempt, err, delay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return stop(context.Cause(ctx))
+		case <-c.clock.After(delay):
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+type fakeClock struct {
+	mutex  sync.Mutex
+	delays []time.Duration
+	after  func()
+}
+
+func (c *fakeClock) After(delay time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	c.delays = append(c.delays, delay)
+	after := c.after
+	c.mutex.Unlock()
+
+	if after != nil {
+		after()
+	}
+
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func (c *fakeClock) Delays() []time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]time.Duration(nil), c.delays...)
+}
+
+var errUnavailable = errors.New("unavailable")
+
+func failing(times int, err error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= times {
+			return err
+		}
+
+		return nil
+	}, &calls
+}
+
+func TestDoSucceedsAfterRetries(t *testing.T) {
+	clock := &fakeClock{}
+	op, calls := failing(2, errUnavailable)
+
+	err := Do(context.Background(), op,
+		WithMaxAttempts(5),
+		WithBackoff(Exponential(10*time.Millisecond, time.Second)),
+		WithClock(clock),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, clock.Delays())
+}
+
+func TestDoMaxAttempts(t *testing.T) {
+	clock := &fakeClock{}
+	op, calls := failing(10, errUnavailable)
+
+	var retries []int
+	err := Do(context.Background(), op,
+		WithMaxAttempts(3),
+		WithBackoff(Constant(time.Millisecond)),
+		WithClock(clock),
+		WithOnRetry(func(attempt int, err error, _ time.Duration) {
+			assert.ErrorIs(t, err, errUnavailable)
+			retries = append(retries, attempt)
+		}),
+	)
+
+	var retryErr *Error
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.ErrorIs(t, err, ErrMaxAttempts)
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []int{1, 2}, retries)
+}
+
+func TestDoPermanentError(t *testing.T) {
+	op, calls := failing(10, Permanent(errUnavailable))
+
+	err := Do(context.Background(), op, WithClock(&fakeClock{}))
+
+	assert.ErrorIs(t, err, ErrPermanent)
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, 1, *calls)
+	assert.Nil(t, Permanent(nil))
+}
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string { return "status error" }
+
+func TestDoRetryIf(t *testing.T) {
+	retryable := func(err error) bool {
+		var status *statusError
+		if errors.As(err, &status) {
+			return status.code >= 500
+		}
+
+		return errors.Is(err, errUnavailable)
+	}
+
+	op, calls := failing(10, &statusError{code: 404})
+	err := Do(context.Background(), op, WithRetryIf(retryable), WithClock(&fakeClock{}))
+	assert.ErrorIs(t, err, ErrPermanent)
+	assert.Equal(t, 1, *calls)
+
+	op, calls = failing(2, &statusError{code: 503})
+	err = Do(context.Background(), op, WithRetryIf(retryable), WithClock(&fakeClock{}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *calls)
+}
+
+func TestDoContextCause(t *testing.T) {
+	shutdown := errors.New("shutdown")
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	clock := &fakeClock{after: func() { cancel(shutdown) }}
+	op, calls := failing(10, errUnavailable)
+
+	err := Do(ctx, op, WithMaxAttempts(0), WithClock(clock))
+
+	assert.ErrorIs(t, err, shutdown)
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, 1, *calls)
+
+	err = Do(ctx, op)
+	var retryErr *Error
+	require.ErrorAs(t, err, &retryErr)
+	assert.Zero(t, retryErr.Attempts)
+	assert.ErrorIs(t, err, shutdown)
+}
+
+func TestDoDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	op, _ := failing(1000, errUnavailable)
+	err := Do(ctx, op, WithMaxAttempts(0), WithBackoff(Constant(time.Millisecond)))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBudgetStopsRetryStorm(t *testing.T) {
+	budget := NewBudget(10, 0.1)
+	clock := &fakeClock{}
+
+	var total int
+	for i := 0; i < 10; i++ {
+		op, calls := failing(100, errUnavailable)
+		err := Do(context.Background(), op, WithMaxAttempts(5), WithBudget(budget), WithClock(clock))
+		assert.Error(t, err)
+		total += *calls
+	}
+
+	assert.Less(t, total, 20)
+	assert.LessOrEqual(t, budget.Tokens(), 5.0)
+
+	op, calls := failing(100, errUnavailable)
+	err := Do(context.Background(), op, WithMaxAttempts(5), WithBudget(budget), WithClock(clock))
+	assert.ErrorIs(t, err, ErrBudgetExhausted)
+	assert.Equal(t, 1, *calls)
+
+	for i := 0; i < 200; i++ {
+		budget.onSuccess()
+	}
+
+	assert.Equal(t, 10.0, budget.Tokens())
+}
+
+func TestBackoffs(t *testing.T) {
+	assert.Equal(t, time.Second, Constant(time.Second).Next(5, 0))
+
+	exponential := Exponential(100*time.Millisecond, time.Second)
+	assert.Equal(t, 100*time.Millisecond, exponential.Next(1, 0))
+	assert.Equal(t, 400*time.Millisecond, exponential.Next(3, 0))
+	assert.Equal(t, time.Second, exponential.Next(5, 0))
+	assert.Equal(t, time.Second, exponential.Next(200, 0))
+
+	fullJitter := ExponentialFullJitter(100*time.Millisecond, time.Second)
+	decorrelated := DecorrelatedJitter(100*time.Millisecond, time.Second)
+
+	var previous time.Duration
+	for attempt := 1; attempt < 100; attempt++ {
+		delay := fullJitter.Next(attempt, 0)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, time.Second)
+
+		next := decorrelated.Next(attempt, previous)
+		assert.GreaterOrEqual(t, next, 100*time.Millisecond)
+		assert.LessOrEqual(t, next, min(time.Second, max(previous, 100*time.Millisecond)*3))
+		previous = next
+	}
+}
@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"context"
+
+	"golang_course/lessons/contexts/ctxkey"
+)
+
+var spanKey = ctxkey.NewKey[SpanContext]("tracing.span")
+
+func WithSpan(ctx context.Context, span SpanContext) context.Context {
+	return ctxkey.WithValue(ctx, spanKey, span)
+}
+
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	return spanKey.Value(ctx)
+}
+
+// TraceIDFromContext returns the hex trace ID of ctx or an empty string.
+func TraceIDFromContext(ctx context.Context) string {
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	return span.TraceID.String()
+}
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"net/http"
+)
+
+// Middleware continues the trace from the incoming traceparent header
+// or starts a new one, and stores the server span in the request context.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var span SpanContext
+		if parent, err := ParseTraceparent(r.Header.Get(TraceparentHeader)); err == nil {
+			span = parent.NewChild()
+		} else {
+			span = NewRoot()
+		}
+
+		ctx := WithSpan(r.Context(), span)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Transport adds the traceparent header of the request context
+// to outgoing requests.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	span, ok := SpanFromContext(r.Context())
+	if !ok {
+		return base.RoundTrip(r)
+	}
+
+	// A RoundTripper must not modify the original request.
+	clone := r.Clone(r.Context())
+	clone.Header.Set(TraceparentHeader, span.Traceparent())
+	return base.RoundTrip(clone)
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogHandler adds trace_id, span_id and parent_span_id attributes
+// to records logged with a traced context.
+type LogHandler struct {
+	slog.Handler
+}
+
+func NewLogHandler(handler slog.Handler) *LogHandler {
+	return &LogHandler{Handler: handler}
+}
+
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if span, ok := SpanFromContext(ctx); ok {
+		record = record.Clone()
+		record.AddAttrs(
+			slog.String("trace_id", span.TraceID.String()),
+			slog.String("span_id", span.SpanID.String()),
+		)
+
+		if span.ParentSpanID.IsValid() {
+			record.AddAttrs(slog.String("parent_span_id", span.ParentSpanID.String()))
+		}
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithGroup(name)}
+}
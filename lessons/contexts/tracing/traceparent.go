@@ -0,0 +1,149 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	TraceparentHeader = "traceparent"
+
+	supportedVersion = "00"
+	sampledFlag      = 0x01
+)
+
+var ErrInvalidTraceparent = errors.New("invalid traceparent header")
+
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+func (id TraceID) IsValid() bool  { return id != TraceID{} }
+func (id SpanID) String() string  { return hex.EncodeToString(id[:]) }
+func (id SpanID) IsValid() bool   { return id != SpanID{} }
+
+// SpanContext is the part of a span that travels between services
+// in the W3C traceparent header.
+type SpanContext struct {
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	Flags        byte
+}
+
+func (c SpanContext) IsValid() bool {
+	return c.TraceID.IsValid() && c.SpanID.IsValid()
+}
+
+func (c SpanContext) Sampled() bool {
+	return c.Flags&sampledFlag != 0
+}
+
+// Traceparent formats the header value: version-traceid-spanid-flags.
+func (c SpanContext) Traceparent() string {
+	return supportedVersion + "-" + c.TraceID.String() + "-" + c.SpanID.String() + "-" + hex.EncodeToString([]byte{c.Flags})
+}
+
+// NewRoot starts a new trace.
+func NewRoot() SpanContext {
+	return SpanContext{
+		TraceID: newTraceID(),
+		SpanID:  newSpanID(),
+		Flags:   sampledFlag,
+	}
+}
+
+// NewChild starts a span in the same trace with c as the parent.
+func (c SpanContext) NewChild() SpanContext {
+	return SpanContext{
+		TraceID:      c.TraceID,
+		SpanID:       newSpanID(),
+		ParentSpanID: c.SpanID,
+		Flags:        c.Flags,
+	}
+}
+
+// ParseTraceparent parses a traceparent header. Versions newer than 00
+// are accepted as long as the known fields are valid, as the
+// specification requires.
+func ParseTraceparent(header string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	version := parts[0]
+	if len(version) != 2 || !isLowerHex(version) || version == "ff" {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	if version == supportedVersion && len(parts) != 4 {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	var c SpanContext
+	if !decodeHex(c.TraceID[:], parts[1]) || !decodeHex(c.SpanID[:], parts[2]) {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	var flags [1]byte
+	if !decodeHex(flags[:], parts[3]) {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	c.Flags = flags[0]
+	if !c.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	return c, nil
+}
+
+func decodeHex(dst []byte, value string) bool {
+	if len(value) != hex.EncodedLen(len(dst)) || !isLowerHex(value) {
+		return false
+	}
+
+	_, err := hex.Decode(dst, []byte(value))
+	return err == nil
+}
+
+func isLowerHex(value string) bool {
+	for idx := 0; idx < len(value); idx++ {
+		symbol := value[idx]
+		if (symbol < '0' || symbol > '9') && (symbol < 'a' || symbol > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		putUint64(id[:8], rand.Uint64())
+		putUint64(id[8:], rand.Uint64())
+	}
+
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		putUint64(id[:], rand.Uint64())
+	}
+
+	return id
+}
+
+func putUint64(dst []byte, value uint64) {
+	for idx := range dst[:8] {
+		dst[idx] = byte(value >> (56 - 8*idx))
+	}
+}
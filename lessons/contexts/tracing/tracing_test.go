@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+func TestParseTraceparent(t *testing.T) {
+	span, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", span.SpanID.String())
+	assert.True(t, span.Sampled())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", span.Traceparent())
+
+	_, err = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+	assert.NoError(t, err)
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+	}
+
+	for _, header := range invalid {
+		_, err := ParseTraceparent(header)
+		assert.ErrorIs(t, err, ErrInvalidTraceparent, header)
+	}
+}
+
+func TestNewRootAndChild(t *testing.T) {
+	root := NewRoot()
+	assert.True(t, root.IsValid())
+	assert.False(t, root.ParentSpanID.IsValid())
+
+	child := root.NewChild()
+	assert.Equal(t, root.TraceID, child.TraceID)
+	assert.Equal(t, root.SpanID, child.ParentSpanID)
+	assert.NotEqual(t, root.SpanID, child.SpanID)
+}
+
+func TestPropagationThroughChainedServers(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&logs, nil)))
+
+	var backendSpan SpanContext
+	backend := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backendSpan, _ = SpanFromContext(r.Context())
+		logger.InfoContext(r.Context(), "backend")
+	})))
+	defer backend.Close()
+
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	var frontendSpan SpanContext
+	frontend := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		frontendSpan, _ = SpanFromContext(r.Context())
+
+		request, err := http.NewRequestWithContext(r.Context(), http.MethodGet, backend.URL, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		response, err := client.Do(request)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+
+		assert.Empty(t, request.Header.Get(TraceparentHeader))
+	})))
+	defer frontend.Close()
+
+	incoming := NewRoot()
+	request, err := http.NewRequest(http.MethodGet, frontend.URL, nil)
+	require.NoError(t, err)
+	request.Header.Set(TraceparentHeader, incoming.Traceparent())
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+
+	assert.Equal(t, incoming.TraceID, frontendSpan.TraceID)
+	assert.Equal(t, incoming.SpanID, frontendSpan.ParentSpanID)
+	assert.Equal(t, incoming.TraceID, backendSpan.TraceID)
+	assert.Equal(t, frontendSpan.SpanID, backendSpan.ParentSpanID)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, incoming.TraceID.String(), record["trace_id"])
+	assert.Equal(t, backendSpan.SpanID.String(), record["span_id"])
+	assert.Equal(t, frontendSpan.SpanID.String(), record["parent_span_id"])
+}
+
+func TestMiddlewareStartsNewTrace(t *testing.T) {
+	var span SpanContext
+	server := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span, _ = SpanFromContext(r.Context())
+		_, _ = io.WriteString(w, TraceIDFromContext(r.Context()))
+	})))
+	defer server.Close()
+
+	request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	request.Header.Set(TraceparentHeader, "broken")
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	assert.True(t, span.IsValid())
+	assert.False(t, span.ParentSpanID.IsValid())
+	assert.Equal(t, span.TraceID.String(), string(body))
+}
+
+func TestLogHandlerWithoutSpan(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&logs, nil))).With("service", "api")
+	logger.InfoContext(context.Background(), "message")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, "api", record["service"])
+	assert.NotContains(t, record, "trace_id")
+	assert.Empty(t, TraceIDFromContext(context.Background()))
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"errlint"
+)
+
+// errlint has its own module, so build it there and run it in the course root:
+// go -C lessons/errors/errlint build -o /tmp/errlint ./cmd/errlint
+// /tmp/errlint ./lessons/errors/...
+
+func main() {
+	multichecker.Main(errlint.Analyzers...)
+}
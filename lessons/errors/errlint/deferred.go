@@ -0,0 +1,33 @@
+package errlint
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var DeferredClose = &analysis.Analyzer{
+	Name:     "deferredclose",
+	Doc:      "reports deferred Close calls whose error is lost",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runDeferredClose,
+}
+
+func runDeferredClose(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.DeferStmt)(nil)}, func(node ast.Node) {
+		call := node.(*ast.DeferStmt).Call
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "Close" || !returnsError(pass.TypesInfo, call) {
+			return
+		}
+
+		pass.Reportf(call.Pos(), "error from deferred %s is not checked, join it into a named result",
+			calleeName(pass.TypesInfo, call))
+	})
+
+	return nil, nil
+}
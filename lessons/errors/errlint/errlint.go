@@ -0,0 +1,63 @@
+// Package errlint contains analyzers for the error handling
+// anti-patterns from the lessons: ignored errors, unchecked deferred
+// Close, errors handled twice, lost wrapping and == with sentinels.
+package errlint
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+var Analyzers = []*analysis.Analyzer{
+	IgnoredError,
+	DeferredClose,
+	LoggedAndReturned,
+	WrapVerb,
+	SentinelCompare,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func isError(t types.Type) bool {
+	return t != nil && types.Identical(t, errorType)
+}
+
+// returnsError reports whether any result of the call is an error.
+func returnsError(info *types.Info, call *ast.CallExpr) bool {
+	switch t := info.TypeOf(call).(type) {
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if isError(t.At(i).Type()) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return isError(t)
+	}
+}
+
+// calleeName returns names like "fmt.Println" or "(*log.Logger).Printf".
+func calleeName(info *types.Info, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok {
+		return ""
+	}
+
+	return fn.FullName()
+}
+
+func enclosingFunc(stack []ast.Node) ast.Node {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return stack[i]
+		}
+	}
+
+	return nil
+}
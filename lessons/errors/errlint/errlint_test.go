@@ -0,0 +1,29 @@
+package errlint
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// go test -v .
+
+func TestIgnoredError(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), IgnoredError, "ignored")
+}
+
+func TestDeferredClose(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), DeferredClose, "deferred")
+}
+
+func TestLoggedAndReturned(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), LoggedAndReturned, "logged/...")
+}
+
+func TestWrapVerb(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), WrapVerb, "wrapverb")
+}
+
+func TestSentinelCompare(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), SentinelCompare, "sentinel")
+}
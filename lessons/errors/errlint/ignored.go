@@ -0,0 +1,69 @@
+package errlint
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var IgnoredError = &analysis.Analyzer{
+	Name:     "ignorederror",
+	Doc:      "reports calls whose error result is silently dropped",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runIgnoredError,
+}
+
+// Functions that return an error only to satisfy an interface
+// or whose errors are conventionally ignored.
+var ignoredErrorExclusions = map[string]bool{
+	"fmt.Print":   true,
+	"fmt.Printf":  true,
+	"fmt.Println": true,
+}
+
+var ignoredErrorReceivers = []string{
+	"(*bytes.Buffer).",
+	"(*strings.Builder).",
+	"(hash.Hash).",
+}
+
+func runIgnoredError(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.ExprStmt)(nil)}, func(node ast.Node) {
+		call, ok := node.(*ast.ExprStmt).X.(*ast.CallExpr)
+		if !ok || !returnsError(pass.TypesInfo, call) {
+			return
+		}
+
+		name := calleeName(pass.TypesInfo, call)
+		if excludedFromIgnoredError(name) {
+			return
+		}
+
+		if name == "" {
+			name = "call"
+		}
+
+		pass.Reportf(call.Pos(), "error returned by %s is not checked", name)
+	})
+
+	return nil, nil
+}
+
+func excludedFromIgnoredError(name string) bool {
+	if ignoredErrorExclusions[name] {
+		return true
+	}
+
+	for _, receiver := range ignoredErrorReceivers {
+		if strings.HasPrefix(name, receiver) {
+			return true
+		}
+	}
+
+	return false
+}
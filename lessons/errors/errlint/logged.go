@@ -0,0 +1,143 @@
+package errlint
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var LoggedAndReturned = &analysis.Analyzer{
+	Name:     "loggedandreturned",
+	Doc:      "reports errors that are logged and returned, so they are handled twice",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runLoggedAndReturned,
+}
+
+var loggingFunctions = map[string]bool{
+	"fmt.Print":   true,
+	"fmt.Printf":  true,
+	"fmt.Println": true,
+	"log.Print":   true,
+	"log.Printf":  true,
+	"log.Println": true,
+}
+
+var loggingReceivers = []string{
+	"(*log.Logger).Print",
+	"(*log/slog.Logger).",
+	"log/slog.",
+}
+
+func isLogging(name string) bool {
+	if loggingFunctions[name] {
+		return true
+	}
+
+	for _, prefix := range loggingReceivers {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func runLoggedAndReturned(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.WithStack([]ast.Node{(*ast.BlockStmt)(nil)}, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		results := errorResults(pass.TypesInfo, enclosingFunc(stack))
+		if results == 0 {
+			return true
+		}
+
+		var logged []*ast.CallExpr
+		for _, stmt := range node.(*ast.BlockStmt).List {
+			switch stmt := stmt.(type) {
+			case *ast.ExprStmt:
+				if call, ok := stmt.X.(*ast.CallExpr); ok && isLogging(calleeName(pass.TypesInfo, call)) {
+					logged = append(logged, call)
+				}
+			case *ast.ReturnStmt:
+				if len(stmt.Results) != results {
+					return true // naked return or return f() with many results
+				}
+
+				returned := errorVariables(pass.TypesInfo, stmt.Results[len(stmt.Results)-1])
+				for _, call := range logged {
+					if mentionsAny(pass.TypesInfo, call.Args, returned) {
+						pass.Reportf(call.Pos(), "error is logged and also returned, handle it once")
+					}
+				}
+
+				return true
+			}
+		}
+
+		return true
+	})
+
+	return nil, nil
+}
+
+// errorVariables returns error variables used in expr, so both
+// return err and return fmt.Errorf("...: %w", err) are matched.
+func errorVariables(info *types.Info, expr ast.Expr) map[types.Object]bool {
+	variables := make(map[types.Object]bool)
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			if variable, ok := info.Uses[ident].(*types.Var); ok && isError(variable.Type()) {
+				variables[variable] = true
+			}
+		}
+
+		return true
+	})
+
+	return variables
+}
+
+func mentionsAny(info *types.Info, args []ast.Expr, variables map[types.Object]bool) bool {
+	for _, arg := range args {
+		for variable := range errorVariables(info, arg) {
+			if variables[variable] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// errorResults returns the number of results of fn when the last one
+// is an error and zero otherwise.
+func errorResults(info *types.Info, fn ast.Node) int {
+	var signature *types.Signature
+	switch fn := fn.(type) {
+	case *ast.FuncDecl:
+		if object, ok := info.Defs[fn.Name].(*types.Func); ok {
+			signature = object.Type().(*types.Signature)
+		}
+	case *ast.FuncLit:
+		signature, _ = info.TypeOf(fn).(*types.Signature)
+	}
+
+	if signature == nil || signature.Results().Len() == 0 {
+		return 0
+	}
+
+	results := signature.Results()
+	if !isError(results.At(results.Len() - 1).Type()) {
+		return 0
+	}
+
+	return results.Len()
+}
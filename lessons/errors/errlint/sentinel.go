@@ -0,0 +1,105 @@
+package errlint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var SentinelCompare = &analysis.Analyzer{
+	Name:     "sentinelcompare",
+	Doc:      "reports == and switch comparisons with sentinel errors, which fail once the error is wrapped",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSentinelCompare,
+}
+
+func runSentinelCompare(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodes := []ast.Node{(*ast.BinaryExpr)(nil), (*ast.SwitchStmt)(nil)}
+	inspect.WithStack(nodes, func(node ast.Node, push bool, stack []ast.Node) bool {
+		// Is methods compare with == by design.
+		if !push || isIsMethod(enclosingFunc(stack)) {
+			return true
+		}
+
+		switch node := node.(type) {
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+
+			if sentinel := sentinelName(pass.TypesInfo, node.Y); sentinel != "" && isError(pass.TypesInfo.TypeOf(node.X)) {
+				reportSentinel(pass, node, sentinel)
+			} else if sentinel := sentinelName(pass.TypesInfo, node.X); sentinel != "" && isError(pass.TypesInfo.TypeOf(node.Y)) {
+				reportSentinel(pass, node, sentinel)
+			}
+		case *ast.SwitchStmt:
+			if node.Tag == nil || !isError(pass.TypesInfo.TypeOf(node.Tag)) {
+				return true
+			}
+
+			for _, stmt := range node.Body.List {
+				for _, expr := range stmt.(*ast.CaseClause).List {
+					if sentinel := sentinelName(pass.TypesInfo, expr); sentinel != "" {
+						reportSentinel(pass, expr, sentinel)
+					}
+				}
+			}
+		}
+
+		return true
+	})
+
+	return nil, nil
+}
+
+func reportSentinel(pass *analysis.Pass, node ast.Node, sentinel string) {
+	pass.Reportf(node.Pos(), "comparison with %s fails for wrapped errors, use errors.Is", sentinel)
+}
+
+// unwrappedSentinels are returned as is by contract, io.Reader returns
+// io.EOF and io.ReadFull returns io.ErrUnexpectedEOF, so == is idiomatic.
+var unwrappedSentinels = map[string]bool{
+	"io.EOF":              true,
+	"io.ErrUnexpectedEOF": true,
+}
+
+// sentinelName returns the name of a package-level error variable,
+// or "" for the ones that are never wrapped.
+func sentinelName(info *types.Info, expr ast.Expr) string {
+	var ident *ast.Ident
+	qualified := false
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident, qualified = expr.Sel, true
+	default:
+		return ""
+	}
+
+	variable, ok := info.Uses[ident].(*types.Var)
+	if !ok || variable.Pkg() == nil || variable.Parent() != variable.Pkg().Scope() || !isError(variable.Type()) {
+		return ""
+	}
+
+	if unwrappedSentinels[variable.Pkg().Path()+"."+variable.Name()] {
+		return ""
+	}
+
+	if !qualified {
+		return variable.Name()
+	}
+
+	return variable.Pkg().Name() + "." + variable.Name()
+}
+
+func isIsMethod(fn ast.Node) bool {
+	decl, ok := fn.(*ast.FuncDecl)
+	return ok && decl.Recv != nil && decl.Name.Name == "Is"
+}
@@ -0,0 +1,47 @@
+package deferred
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+)
+
+// From lessons/errors/errors_defer_ignoring.
+
+func getBalance(database *sql.DB, clientId int) (float32, error) {
+	query := "..."
+	rows, err := database.Query(query, clientId)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close() // want `error from deferred \(\*database/sql.Rows\).Close is not checked`
+
+	// reading...
+	return 0., nil
+}
+
+func getBalanceFixed(database *sql.DB, clientId int) (balance float32, err error) {
+	query := "..."
+	rows, err := database.Query(query, clientId)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	// reading...
+	return 0., nil
+}
+
+func readFile(name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close() // want `error from deferred \(\*os.File\).Close is not checked`
+	return nil
+}
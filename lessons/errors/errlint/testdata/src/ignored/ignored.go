@@ -0,0 +1,29 @@
+package ignored
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// From lessons/errors/errors_ignoring.
+
+func process() error {
+	return errors.New("error")
+}
+
+func main() {
+	process() // want `error returned by ignored.process is not checked`
+
+	os.Remove("file") // want `error returned by os.Remove is not checked`
+
+	_ = process()
+	if err := process(); err != nil {
+		fmt.Println(err)
+	}
+
+	var builder strings.Builder
+	builder.WriteString("text")
+	fmt.Println(builder.String())
+}
@@ -0,0 +1,42 @@
+package bad
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+)
+
+// From lessons/errors/many_times_handling_1.
+
+func GetRoute(lat, lon float32) (string, error) {
+	err := validateCoordinates(lat, lon)
+	if err != nil {
+		fmt.Println("incorrect coordinates:", err) // want `error is logged and also returned, handle it once`
+		return "", err
+	}
+
+	return "route", nil
+}
+
+func validateCoordinates(lat, lon float32) error {
+	if lat > 90. || lat < -90. {
+		err := errors.New("incorrect latitude")
+		log.Printf("validation: %v", err.Error()) // want `error is logged and also returned, handle it once`
+		return err
+	}
+	if lon > 180. || lon < -180. {
+		err := errors.New("incorrect longitude")
+		slog.Error("validation", "error", err) // want `error is logged and also returned, handle it once`
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func handle() {
+	if _, err := GetRoute(0, 0); err != nil {
+		log.Println(err)
+		return
+	}
+}
@@ -0,0 +1,53 @@
+package good
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// From lessons/errors/many_times_handling_2.
+
+func GetRoute(lat, lon float32) (string, error) {
+	err := validateCoordinates(lat, lon)
+	if err != nil {
+		return "", fmt.Errorf("validation error: %w", err)
+	}
+
+	return "route", nil
+}
+
+func validateCoordinates(lat, lon float32) error {
+	if lat > 90. || lat < -90. {
+		return errors.New("incorrect latitude")
+	}
+	if lon > 180. || lon < -180. {
+		return errors.New("incorrect longitude")
+	}
+
+	return nil
+}
+
+type server struct{}
+
+func (s *server) ListenAndServe() error {
+	return nil
+}
+
+// The log call doesn't mention the returned error.
+func serve(s *server) error {
+	log.Println("starting server")
+	return s.ListenAndServe()
+}
+
+func process() error {
+	fmt.Println("processing")
+	return nil
+}
+
+// The logged error isn't the returned one.
+func retry(first error) error {
+	log.Println("first attempt failed:", first)
+	second := errors.New("second attempt failed")
+	return second
+}
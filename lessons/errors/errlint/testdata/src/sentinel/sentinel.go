@@ -0,0 +1,72 @@
+package sentinel
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// From lessons/errors/errors_value_checking and signal_errors.
+
+var ErrDatabaseProblem = errors.New("database problem")
+
+func GetDataFromDB() error {
+	return fmt.Errorf("failed to get data: %w", ErrDatabaseProblem)
+}
+
+func main() {
+	err := GetDataFromDB()
+	if err == ErrDatabaseProblem { // want `comparison with ErrDatabaseProblem fails for wrapped errors, use errors.Is`
+		fmt.Println(err.Error())
+	} else if err != nil {
+		fmt.Println("unknown error")
+	}
+
+	if errors.Is(err, ErrDatabaseProblem) {
+		fmt.Println(err.Error())
+	}
+}
+
+type Database interface {
+	Query(string) (string, error)
+}
+
+func RunQueyry(db Database, query string) {
+	_, err := db.Query(query)
+	if sql.ErrNoRows != err { // want `comparison with sql.ErrNoRows fails for wrapped errors, use errors.Is`
+		return
+	}
+
+	switch err {
+	case nil:
+	case io.EOF, sql.ErrNoRows: // want `comparison with sql.ErrNoRows fails`
+	}
+}
+
+// Readers return io.EOF unwrapped, so == is the idiomatic check.
+func ReadAll(reader io.Reader) error {
+	buffer := make([]byte, 512)
+	for {
+		_, err := reader.Read(buffer)
+		if err == io.EOF {
+			return nil
+		}
+
+		if _, err := io.ReadFull(reader, buffer); err == io.ErrUnexpectedEOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string { return "not found" }
+
+func (notFoundError) Is(target error) bool {
+	return target == ErrDatabaseProblem
+}
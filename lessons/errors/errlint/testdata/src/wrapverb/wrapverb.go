@@ -0,0 +1,28 @@
+package wrapverb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// From lessons/errors/incorrect_wrapping.
+
+func main() {
+	err1 := errors.New("source error 1")
+	err2 := errors.New("source error 2")
+	err := fmt.Errorf("additional error information: %w and %w", err1, err2)
+
+	fmt.Println(err.Error())
+	fmt.Println(errors.Unwrap(err))
+
+	err = fmt.Errorf("additional error information: %w", "error") // want `%w is used with a non-error argument`
+
+	err = fmt.Errorf("failed to get data: %v", err1) // want `error is formatted with %v and can't be unwrapped, use %w`
+	err = fmt.Errorf("%d: %s", 42, err1)             // want `error is formatted with %s and can't be unwrapped, use %w`
+	err = fmt.Errorf("%*d: %v", 5, 42, err1)         // want `error is formatted with %v and can't be unwrapped, use %w`
+
+	err = fmt.Errorf("100%% failed: %w, also %v", err1, err2)
+	err = fmt.Errorf("with stack: %+v", err1)
+	err = fmt.Errorf("%[1]v", err1)
+	fmt.Println(err)
+}
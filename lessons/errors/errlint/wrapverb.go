@@ -0,0 +1,116 @@
+package errlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var WrapVerb = &analysis.Analyzer{
+	Name:     "wrapverb",
+	Doc:      "reports fmt.Errorf calls that format errors with %v instead of wrapping them with %w",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runWrapVerb,
+}
+
+type formatVerb struct {
+	verb rune
+	plus bool
+	arg  int
+}
+
+func runWrapVerb(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		if calleeName(pass.TypesInfo, call) != "fmt.Errorf" || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return
+		}
+
+		format := pass.TypesInfo.Types[call.Args[0]].Value
+		if format == nil || format.Kind() != constant.String {
+			return
+		}
+
+		verbs, ok := parseFormat(constant.StringVal(format))
+		if !ok {
+			return
+		}
+
+		wraps := false
+		for _, verb := range verbs {
+			wraps = wraps || verb.verb == 'w'
+		}
+
+		args := call.Args[1:]
+		for _, verb := range verbs {
+			if verb.arg >= len(args) {
+				return
+			}
+
+			arg := args[verb.arg]
+			argIsError := isError(pass.TypesInfo.TypeOf(arg))
+
+			switch {
+			case verb.verb == 'w' && !argIsError:
+				pass.Reportf(arg.Pos(), "%%w is used with a non-error argument")
+			case !wraps && !verb.plus && (verb.verb == 'v' || verb.verb == 's') && argIsError:
+				pass.Reportf(arg.Pos(), "error is formatted with %%%c and can't be unwrapped, use %%w", verb.verb)
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+// parseFormat returns the verbs with the indexes of their arguments.
+// Formats with explicit argument indexes aren't supported.
+func parseFormat(format string) ([]formatVerb, bool) {
+	var verbs []formatVerb
+
+	arg := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		i++
+		plus := false
+		for ; i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0; i++ {
+			plus = plus || format[i] == '+'
+		}
+
+		for ; i < len(format); i++ {
+			c := format[i]
+			if c == '[' {
+				return nil, false
+			} else if c == '*' {
+				arg++
+			} else if (c < '0' || c > '9') && c != '.' {
+				break
+			}
+		}
+
+		if i >= len(format) {
+			break
+		}
+
+		if format[i] == '%' {
+			continue
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+
+		verbs = append(verbs, formatVerb{verb: verb, plus: plus, arg: arg})
+		arg++
+	}
+
+	return verbs, true
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang_course/lessons/errors/status"
+)
+
+func divide(lhs, rhs int) (int, error) {
+	if rhs == 0 {
+		return 0, status.New(status.InvalidArgument, "division by zero").WithDetail("lhs", lhs)
+	} else if lhs%2 == 0 || rhs%2 == 0 {
+		return 0, status.Newf(status.FailedPrecondition, "%d or %d is even", lhs, rhs)
+	}
+
+	return lhs / rhs, nil
+}
+
+func main() {
+	x := 100
+	y := 0
+
+	value, err := divide(x, y)
+	err = fmt.Errorf("calculate: %w", err)
+	fmt.Println(value, err)
+
+	code := status.CodeOf(err)
+	fmt.Println(code, code.HTTPStatus(), code.ExitCode())
+
+	data, _ := json.Marshal(status.Convert(err))
+	fmt.Println(string(data))
+
+	status.Exit(err)
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 
-	"github.com/pkg/errors"
+	"golang_course/lessons/errors/errors"
 )
 
 func main() {
@@ -0,0 +1,147 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+)
+
+type fundamental struct {
+	message string
+	trace   stack
+}
+
+// New returns an error with the stack trace of the caller.
+func New(message string) error {
+	err := &fundamental{message: message}
+	err.trace.capture(1)
+	return err
+}
+
+// Errorf formats like fmt.Errorf, %w is supported, and records the stack.
+func Errorf(format string, args ...any) error {
+	err := &withStack{cause: fmt.Errorf(format, args...)}
+	err.trace.capture(1)
+	return err
+}
+
+func (e *fundamental) Error() string { return e.message }
+func (e *fundamental) stack() *stack { return &e.trace }
+
+func (e *fundamental) Format(state fmt.State, verb rune) {
+	formatError(state, verb, e.message, &e.trace)
+}
+
+type withStack struct {
+	cause error
+	trace stack
+}
+
+// WithStack records the stack of the caller for err.
+// It returns nil when err is nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := &withStack{cause: err}
+	wrapped.trace.capture(1)
+	return wrapped
+}
+
+func (e *withStack) Error() string { return e.cause.Error() }
+func (e *withStack) Unwrap() error { return e.cause }
+func (e *withStack) stack() *stack { return &e.trace }
+
+func (e *withStack) Format(state fmt.State, verb rune) {
+	if verb == 'v' && state.Flag('+') {
+		if _, ok := e.cause.(fmt.Formatter); ok {
+			fmt.Fprintf(state, "%+v", e.cause)
+		} else {
+			_, _ = io.WriteString(state, e.cause.Error())
+		}
+
+		e.trace.write(state)
+		return
+	}
+
+	formatError(state, verb, e.Error(), nil)
+}
+
+type withMessage struct {
+	cause   error
+	message string
+	trace   *stack
+}
+
+// Wrap adds message to err. The stack is recorded only when err doesn't
+// have one yet, so wrapping on every level doesn't repeat the capture.
+// It returns nil when err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	return wrap(err, message)
+}
+
+// Wrapf is Wrap with a formatted message.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	return wrap(err, fmt.Sprintf(format, args...))
+}
+
+func wrap(err error, message string) error {
+	wrapped := &withMessage{cause: err, message: message}
+	if !hasStack(err) {
+		wrapped.trace = &stack{}
+		wrapped.trace.capture(2)
+	}
+
+	return wrapped
+}
+
+func (e *withMessage) Error() string { return e.message + ": " + e.cause.Error() }
+func (e *withMessage) Unwrap() error { return e.cause }
+
+func (e *withMessage) stack() *stack {
+	return e.trace
+}
+
+func (e *withMessage) Format(state fmt.State, verb rune) {
+	if verb == 'v' && state.Flag('+') {
+		fmt.Fprintf(state, "%+v\n%s", e.cause, e.message)
+		if e.trace != nil {
+			e.trace.write(state)
+		}
+
+		return
+	}
+
+	formatError(state, verb, e.Error(), nil)
+}
+
+func formatError(state fmt.State, verb rune, message string, trace *stack) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(state, message)
+		if state.Flag('+') && trace != nil {
+			trace.write(state)
+		}
+	case 's':
+		_, _ = io.WriteString(state, message)
+	case 'q':
+		fmt.Fprintf(state, "%q", message)
+	}
+}
+
+// The functions below make the package a drop-in replacement
+// for the standard errors package.
+
+func Is(err, target error) bool     { return stderrors.Is(err, target) }
+func As(err error, target any) bool { return stderrors.As(err, target) }
+func Unwrap(err error) error        { return stderrors.Unwrap(err) }
+func Join(errs ...error) error      { return stderrors.Join(errs...) }
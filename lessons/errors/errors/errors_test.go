@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string { return fmt.Sprintf("code %d", e.code) }
+
+func TestNew(t *testing.T) {
+	err := New("some error")
+
+	assert.Equal(t, "some error", err.Error())
+	assert.Equal(t, "some error", fmt.Sprintf("%v", err))
+	assert.Equal(t, "some error", fmt.Sprintf("%s", err))
+	assert.Equal(t, `"some error"`, fmt.Sprintf("%q", err))
+
+	trace := fmt.Sprintf("%+v", err)
+	assert.Contains(t, trace, "some error\n")
+	assert.Contains(t, trace, "errors.TestNew\n")
+	assert.Contains(t, trace, "errors_test.go:")
+}
+
+func TestStackTrace(t *testing.T) {
+	err := New("some error")
+
+	frames := StackTrace(err)
+	require.NotEmpty(t, frames)
+	assert.Contains(t, frames[0].Function, "TestStackTrace")
+
+	assert.Empty(t, StackTrace(fs.ErrNotExist))
+	assert.Empty(t, StackTrace(nil))
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(nil, "message"))
+	assert.Nil(t, Wrapf(nil, "message %d", 1))
+
+	err := Wrapf(fs.ErrNotExist, "open %s", "config.yaml")
+	assert.Equal(t, "open config.yaml: file does not exist", err.Error())
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Contains(t, StackTrace(err)[0].Function, "TestWrap")
+
+	trace := fmt.Sprintf("%+v", err)
+	assert.Contains(t, trace, "file does not exist\nopen config.yaml\n")
+	assert.Contains(t, trace, "errors.TestWrap\n")
+}
+
+func TestWrapKeepsOriginalStack(t *testing.T) {
+	cause := New("cause")
+	err := Wrap(Wrap(cause, "first"), "second")
+
+	assert.Equal(t, "second: first: cause", err.Error())
+	assert.Equal(t, StackTrace(cause), StackTrace(err))
+
+	trace := fmt.Sprintf("%+v", err)
+	assert.Equal(t, 1, strings.Count(trace, "errors.TestWrapKeepsOriginalStack\n"))
+	assert.Contains(t, trace, "\nfirst\nsecond")
+}
+
+func TestErrorf(t *testing.T) {
+	target := &codeError{code: 42}
+	err := Errorf("request failed: %w", target)
+
+	assert.Equal(t, "request failed: code 42", err.Error())
+
+	var code *codeError
+	require.True(t, As(err, &code))
+	assert.Equal(t, 42, code.code)
+	assert.True(t, Is(err, target))
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestErrorf\n")
+}
+
+func TestWithStack(t *testing.T) {
+	assert.Nil(t, WithStack(nil))
+
+	err := WithStack(fs.ErrPermission)
+	assert.Equal(t, fs.ErrPermission.Error(), err.Error())
+	assert.Equal(t, fs.ErrPermission, Unwrap(err))
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestWithStack\n")
+}
+
+func TestJoin(t *testing.T) {
+	first := New("first")
+	err := Join(first, Wrap(fs.ErrNotExist, "second"))
+
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+const maxStackDepth = 32
+
+// stack keeps only program counters. Turning them into function names
+// and lines is much more expensive, so it is done when the error is printed.
+type stack struct {
+	pcs   [maxStackDepth]uintptr
+	depth int
+}
+
+func (s *stack) capture(skip int) {
+	s.depth = runtime.Callers(skip+2, s.pcs[:])
+}
+
+func (s *stack) frames() []runtime.Frame {
+	if s == nil || s.depth == 0 {
+		return nil
+	}
+
+	result := make([]runtime.Frame, 0, s.depth)
+	frames := runtime.CallersFrames(s.pcs[:s.depth])
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+		if !more {
+			return result
+		}
+	}
+}
+
+func (s *stack) write(w io.Writer) {
+	for _, frame := range s.frames() {
+		fmt.Fprintf(w, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+}
+
+type stackTracer interface {
+	stack() *stack
+}
+
+// StackTrace returns the frames of the deepest stack in the chain of err,
+// which is the closest one to the place where the error was created.
+func StackTrace(err error) []runtime.Frame {
+	var found *stack
+	for err != nil {
+		if tracer, ok := err.(stackTracer); ok {
+			found = tracer.stack()
+		}
+
+		err = Unwrap(err)
+	}
+
+	return found.frames()
+}
+
+func hasStack(err error) bool {
+	var tracer stackTracer
+	return As(err, &tracer)
+}
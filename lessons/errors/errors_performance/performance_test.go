@@ -2,14 +2,16 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
-	othererrors "github.com/pkg/errors"
+	othererrors "golang_course/lessons/errors/errors"
 )
 
-// go test -bench=. performance_test.go
+// go test -bench=. -benchmem performance_test.go
 
 var err error
+var text string
 
 func BenchmarkErrorWithoutStackTrace(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -22,3 +24,38 @@ func BenchmarkErrorWithStackTrace(b *testing.B) {
 		err = othererrors.New("error")
 	}
 }
+
+func BenchmarkWrapWithoutStackTrace(b *testing.B) {
+	cause := errors.New("error")
+	for i := 0; i < b.N; i++ {
+		err = fmt.Errorf("wrapped: %w", cause)
+	}
+}
+
+func BenchmarkWrapWithStackTrace(b *testing.B) {
+	cause := errors.New("error")
+	for i := 0; i < b.N; i++ {
+		err = othererrors.Wrap(cause, "wrapped")
+	}
+}
+
+func BenchmarkWrapErrorWithStackTrace(b *testing.B) {
+	cause := othererrors.New("error")
+	for i := 0; i < b.N; i++ {
+		err = othererrors.Wrap(cause, "wrapped") // the stack is already there
+	}
+}
+
+func BenchmarkPrintWithoutStackTrace(b *testing.B) {
+	cause := othererrors.New("error")
+	for i := 0; i < b.N; i++ {
+		text = fmt.Sprintf("%v", cause)
+	}
+}
+
+func BenchmarkPrintWithStackTrace(b *testing.B) {
+	cause := othererrors.New("error")
+	for i := 0; i < b.N; i++ {
+		text = fmt.Sprintf("%+v", cause)
+	}
+}
@@ -0,0 +1,161 @@
+package multierr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// FormatFunc builds the message of Error from the collected errors.
+type FormatFunc func(errs []error) string
+
+// ListFormatFunc is the default format:
+//
+//	2 errors occurred:
+//		* error 1
+//		* error 2
+func ListFormatFunc(errs []error) string {
+	if len(errs) == 1 {
+		return fmt.Sprintf("1 error occurred:\n\t* %s\n\n", errs[0])
+	}
+
+	points := make([]string, len(errs))
+	for idx, err := range errs {
+		points[idx] = fmt.Sprintf("* %s", err)
+	}
+
+	return fmt.Sprintf("%d errors occurred:\n\t%s\n\n", len(errs), strings.Join(points, "\n\t"))
+}
+
+// Error collects several errors. The zero value is ready to use and
+// is safe for concurrent appends. Nested *Error values are flattened,
+// and errors.Is / errors.As see every collected error through Unwrap.
+type Error struct {
+	mutex     sync.RWMutex
+	errs      []error
+	Formatter FormatFunc
+}
+
+// Append adds errs to err. When err is an *Error it is extended in place,
+// otherwise a new *Error is created. nil errors are skipped, and the
+// result is nil when there are no errors at all, so err = Append(err, e)
+// doesn't fall into the typed nil trap.
+func Append(err error, errs ...error) error {
+	target, ok := err.(*Error)
+	if !ok || target == nil {
+		target = &Error{}
+		if err != nil {
+			target.Append(err)
+		}
+	}
+
+	return target.Append(errs...).ErrorOrNil()
+}
+
+// Combine returns nil when all errs are nil.
+func Combine(errs ...error) error {
+	return Append(nil, errs...)
+}
+
+func (e *Error) Append(errs ...error) *Error {
+	flattened := make([]error, 0, len(errs))
+	for _, err := range errs {
+		switch err := err.(type) {
+		case nil:
+		case *Error:
+			if err != nil {
+				flattened = append(flattened, err.Errors()...)
+			}
+		default:
+			flattened = append(flattened, err)
+		}
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.errs = append(e.errs, flattened...)
+	return e
+}
+
+// ErrorOrNil returns nil for a nil or empty Error. A nil *Error stored
+// in an error interface is not equal to nil, see the interface_not_nil lessons.
+func (e *Error) ErrorOrNil() error {
+	if e == nil || e.Len() == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *Error) Len() int {
+	if e == nil {
+		return 0
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return len(e.errs)
+}
+
+// Errors returns a copy of the collected errors.
+func (e *Error) Errors() []error {
+	if e == nil {
+		return nil
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if len(e.errs) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(e.errs))
+	copy(errs, e.errs)
+	return errs
+}
+
+func (e *Error) Error() string {
+	errs := e.Errors()
+	if len(errs) == 0 {
+		return "no errors"
+	}
+
+	formatter := e.Formatter
+	if formatter == nil {
+		formatter = ListFormatFunc
+	}
+
+	return formatter(errs)
+}
+
+// Unwrap lets errors.Is and errors.As check every collected error.
+func (e *Error) Unwrap() []error {
+	return e.Errors()
+}
+
+// Format prints the details of every error for %+v, so wrapped errors
+// with stack traces keep them. Other verbs print Error().
+func (e *Error) Format(state fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && state.Flag('+'):
+		errs := e.Errors()
+		if len(errs) == 1 {
+			fmt.Fprint(state, "1 error occurred:")
+		} else {
+			fmt.Fprintf(state, "%d errors occurred:", len(errs))
+		}
+
+		for idx, err := range errs {
+			details := fmt.Sprintf("%+v", err)
+			fmt.Fprintf(state, "\n[%d] %s", idx+1, strings.ReplaceAll(details, "\n", "\n    "))
+		}
+	case verb == 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	default:
+		_, _ = io.WriteString(state, e.Error())
+	}
+}
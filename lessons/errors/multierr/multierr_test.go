@@ -0,0 +1,148 @@
+package multierr
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+var (
+	errFirst  = errors.New("error 1")
+	errSecond = errors.New("error 2")
+	errThird  = errors.New("error 3")
+)
+
+func TestAppend(t *testing.T) {
+	var err error
+	err = Append(err, errFirst)
+	err = Append(err, errSecond)
+
+	assert.EqualError(t, err, "2 errors occurred:\n\t* error 1\n\t* error 2\n\n")
+	assert.EqualError(t, Append(nil, errFirst), "1 error occurred:\n\t* error 1\n\n")
+
+	err = Append(errFirst, nil, errSecond, nil)
+	assert.Equal(t, []error{errFirst, errSecond}, err.(*Error).Errors())
+}
+
+func TestAppendFlattensNestedErrors(t *testing.T) {
+	inner := Append(nil, errSecond, errThird)
+	outer := Append(errFirst, inner, Append(nil, Append(nil, errFirst))).(*Error)
+
+	assert.Equal(t, []error{errFirst, errSecond, errThird, errFirst}, outer.Errors())
+	assert.Equal(t, 4, outer.Len())
+
+	outer.Append(outer)
+	assert.Equal(t, 8, outer.Len())
+}
+
+func TestErrorsIsAndAs(t *testing.T) {
+	_, openErr := os.Open("/definitely/missing/file")
+	err := fmt.Errorf("loading config: %w", Append(errFirst, openErr))
+
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NotErrorIs(t, err, errThird)
+
+	var pathErr *fs.PathError
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, "/definitely/missing/file", pathErr.Path)
+
+	joined := errors.Join(errThird, Append(nil, errSecond))
+	assert.ErrorIs(t, joined, errSecond)
+	assert.ErrorIs(t, Append(nil, joined), errThird)
+}
+
+func returnsNothing() error {
+	var result *Error
+	return result.ErrorOrNil()
+}
+
+func TestNilResult(t *testing.T) {
+	assert.NoError(t, returnsNothing())
+	assert.NoError(t, Append(nil))
+	assert.NoError(t, Append(nil, nil, nil))
+	assert.NoError(t, Combine(nil, nil))
+	assert.Error(t, Combine(nil, errFirst))
+
+	var err error
+	for _, next := range []error{nil, nil} {
+		err = Append(err, next)
+	}
+
+	assert.True(t, err == nil)
+	assert.NoError(t, Append(&Error{}, nil))
+
+	var empty Error
+	assert.NoError(t, empty.ErrorOrNil())
+	assert.Equal(t, "no errors", empty.Error())
+	assert.Nil(t, empty.Unwrap())
+
+	var typedNil *Error
+	err = typedNil
+	assert.True(t, err != nil)
+	assert.NoError(t, Append(typedNil, nil))
+	assert.Equal(t, 0, typedNil.Len())
+}
+
+type detailedError struct{}
+
+func (detailedError) Error() string { return "detailed" }
+
+func (detailedError) Format(state fmt.State, verb rune) {
+	if verb == 'v' && state.Flag('+') {
+		fmt.Fprint(state, "detailed\nline 1\nline 2")
+		return
+	}
+
+	fmt.Fprint(state, "detailed")
+}
+
+func TestFormatting(t *testing.T) {
+	err := Append(errFirst, detailedError{})
+
+	assert.Equal(t, "1 error occurred:\n[1] error 1", fmt.Sprintf("%+v", Append(nil, errFirst)))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%v", err))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%s", err))
+	assert.Equal(t, fmt.Sprintf("%q", err.Error()), fmt.Sprintf("%q", err))
+	assert.Equal(t, "2 errors occurred:\n[1] error 1\n[2] detailed\n    line 1\n    line 2", fmt.Sprintf("%+v", err))
+}
+
+func TestCustomFormatter(t *testing.T) {
+	err := Append(errFirst, errSecond).(*Error)
+	err.Formatter = func(errs []error) string {
+		messages := make([]string, len(errs))
+		for idx, err := range errs {
+			messages[idx] = err.Error()
+		}
+
+		return strings.Join(messages, "; ")
+	}
+
+	assert.EqualError(t, err, "error 1; error 2")
+}
+
+func TestConcurrentAppend(t *testing.T) {
+	var collected Error
+
+	wg := sync.WaitGroup{}
+	wg.Add(100)
+	for i := 0; i < 100; i++ {
+		go func(idx int) {
+			defer wg.Done()
+			collected.Append(fmt.Errorf("error %d", idx))
+			_ = collected.Error()
+		}(i)
+	}
+
+	wg.Wait()
+	assert.Equal(t, 100, collected.Len())
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/hashicorp/go-multierror"
+	"golang_course/lessons/errors/multierr"
 )
 
 var (
@@ -18,8 +18,8 @@ var (
 
 func main() {
 	var err error
-	err = multierror.Append(err, ErrNumber1)
-	err = multierror.Append(err, ErrNumber2)
+	err = multierr.Append(err, ErrNumber1)
+	err = multierr.Append(err, ErrNumber2)
 	err = fmt.Errorf("internal error: %w", err)
 
 	if errors.Is(err, ErrNumber1) {
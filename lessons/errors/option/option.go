@@ -0,0 +1,184 @@
+package option
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Option holds a value or nothing. The zero value is None.
+type Option[T any] struct {
+	value   T
+	present bool
+}
+
+func Some[T any](value T) Option[T] {
+	return Option[T]{value: value, present: true}
+}
+
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// FromPair converts the (value, ok) idiom.
+func FromPair[T any](value T, ok bool) Option[T] {
+	if !ok {
+		return None[T]()
+	}
+
+	return Some(value)
+}
+
+// FromPointer returns None for nil and a copy of the pointed value otherwise.
+func FromPointer[T any](value *T) Option[T] {
+	if value == nil {
+		return None[T]()
+	}
+
+	return Some(*value)
+}
+
+func FromNull[T any](value sql.Null[T]) Option[T] {
+	return FromPair(value.V, value.Valid)
+}
+
+func (o Option[T]) IsSome() bool {
+	return o.present
+}
+
+func (o Option[T]) IsNone() bool {
+	return !o.present
+}
+
+// Get returns the value in the (value, ok) form.
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// Unwrap returns the value and panics when there is none.
+func (o Option[T]) Unwrap() T {
+	return o.Expect("called Unwrap on None")
+}
+
+// Expect is Unwrap with a custom panic message.
+func (o Option[T]) Expect(message string) T {
+	if !o.present {
+		panic(message)
+	}
+
+	return o.value
+}
+
+func (o Option[T]) OrElse(fallback T) T {
+	if !o.present {
+		return fallback
+	}
+
+	return o.value
+}
+
+func (o Option[T]) OrElseFunc(fallback func() T) T {
+	if !o.present {
+		return fallback()
+	}
+
+	return o.value
+}
+
+// Or returns o if it has a value and other otherwise.
+func (o Option[T]) Or(other Option[T]) Option[T] {
+	if !o.present {
+		return other
+	}
+
+	return o
+}
+
+func (o Option[T]) Filter(predicate func(T) bool) Option[T] {
+	if !o.present || !predicate(o.value) {
+		return None[T]()
+	}
+
+	return o
+}
+
+// Pointer returns nil for None and a pointer to a copy of the value otherwise.
+func (o Option[T]) Pointer() *T {
+	if !o.present {
+		return nil
+	}
+
+	value := o.value
+	return &value
+}
+
+func (o Option[T]) Null() sql.Null[T] {
+	return sql.Null[T]{V: o.value, Valid: o.present}
+}
+
+func (o Option[T]) String() string {
+	if !o.present {
+		return "None"
+	}
+
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+// Map and FlatMap are functions because methods can't have type parameters.
+
+func Map[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.present {
+		return None[U]()
+	}
+
+	return Some(f(o.value))
+}
+
+func FlatMap[T, U any](o Option[T], f func(T) Option[U]) Option[U] {
+	if !o.present {
+		return None[U]()
+	}
+
+	return f(o.value)
+}
+
+// MarshalJSON encodes None as null.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.present {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.value)
+}
+
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = None[T]()
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*o = Some(value)
+	return nil
+}
+
+// Scan implements sql.Scanner, so Option can be used instead of sql.NullXXX.
+func (o *Option[T]) Scan(src any) error {
+	var null sql.Null[T]
+	if err := null.Scan(src); err != nil {
+		return err
+	}
+
+	*o = FromNull(null)
+	return nil
+}
+
+func (o Option[T]) Value() (driver.Value, error) {
+	return o.Null().Value()
+}
@@ -0,0 +1,121 @@
+package option
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+func TestSomeAndNone(t *testing.T) {
+	some := Some(10)
+	value, ok := some.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+	assert.True(t, some.IsSome())
+	assert.Equal(t, 10, some.Unwrap())
+	assert.Equal(t, "Some(10)", some.String())
+
+	var none Option[int]
+	assert.True(t, none.IsNone())
+	assert.Equal(t, None[int](), none)
+	assert.Equal(t, 5, none.OrElse(5))
+	assert.Equal(t, 7, none.OrElseFunc(func() int { return 7 }))
+	assert.Equal(t, "None", none.String())
+	assert.PanicsWithValue(t, "called Unwrap on None", func() { none.Unwrap() })
+	assert.PanicsWithValue(t, "no user", func() { none.Expect("no user") })
+}
+
+func TestConversions(t *testing.T) {
+	lookup := map[string]int{"one": 1}
+
+	assert.Equal(t, Some(1), FromPair(lookup["one"], true))
+	value, ok := lookup["two"]
+	assert.Equal(t, None[int](), FromPair(value, ok))
+
+	number := 3
+	assert.Equal(t, Some(3), FromPointer(&number))
+	assert.Equal(t, None[int](), FromPointer[int](nil))
+
+	pointer := Some(3).Pointer()
+	require.NotNil(t, pointer)
+	assert.Equal(t, 3, *pointer)
+	assert.Nil(t, None[int]().Pointer())
+
+	assert.Equal(t, sql.Null[string]{V: "text", Valid: true}, Some("text").Null())
+	assert.Equal(t, Some("text"), FromNull(sql.Null[string]{V: "text", Valid: true}))
+	assert.Equal(t, None[string](), FromNull(sql.Null[string]{}))
+}
+
+func TestCombinators(t *testing.T) {
+	parse := func(text string) Option[int] {
+		number, err := strconv.Atoi(text)
+		return FromPair(number, err == nil)
+	}
+
+	assert.Equal(t, Some("10"), Map(Some(10), strconv.Itoa))
+	assert.Equal(t, None[string](), Map(None[int](), strconv.Itoa))
+
+	assert.Equal(t, Some(42), FlatMap(Some("42"), parse))
+	assert.Equal(t, None[int](), FlatMap(Some("forty two"), parse))
+	assert.Equal(t, None[int](), FlatMap(None[string](), parse))
+
+	even := func(value int) bool { return value%2 == 0 }
+	assert.Equal(t, Some(2), Some(2).Filter(even))
+	assert.Equal(t, None[int](), Some(3).Filter(even))
+
+	assert.Equal(t, Some(1), Some(1).Or(Some(2)))
+	assert.Equal(t, Some(2), None[int]().Or(Some(2)))
+}
+
+func TestJSON(t *testing.T) {
+	type user struct {
+		Name  string         `json:"name"`
+		Email Option[string] `json:"email"`
+	}
+
+	data, err := json.Marshal(user{Name: "bob", Email: Some("bob@example.com")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"bob","email":"bob@example.com"}`, string(data))
+
+	data, err = json.Marshal(user{Name: "bob"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"bob","email":null}`, string(data))
+
+	var decoded user
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"bob","email":"bob@example.com"}`), &decoded))
+	assert.Equal(t, Some("bob@example.com"), decoded.Email)
+
+	decoded = user{Email: Some("old")}
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"bob","email":null}`), &decoded))
+	assert.Equal(t, None[string](), decoded.Email)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"email":1}`), &decoded))
+}
+
+func TestSQL(t *testing.T) {
+	var text Option[string]
+	require.NoError(t, text.Scan("value"))
+	assert.Equal(t, Some("value"), text)
+
+	require.NoError(t, text.Scan(nil))
+	assert.Equal(t, None[string](), text)
+
+	var number Option[int64]
+	require.NoError(t, number.Scan(int64(42)))
+	assert.Equal(t, Some(int64(42)), number)
+	assert.Error(t, number.Scan("not a number"))
+
+	value, err := Some(int64(42)).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), value)
+
+	value, err = None[int64]().Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
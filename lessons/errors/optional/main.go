@@ -1,6 +1,12 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"golang_course/lessons/errors/option"
+	"golang_course/lessons/errors/result"
+)
 
 var NullOptional = Optional[int]{}
 
@@ -33,10 +39,29 @@ func divide(lhs, rhs int) Optional[int] {
 	return NewOptional(result)
 }
 
+func divideV2(lhs, rhs int) option.Option[int] {
+	if rhs == 0 {
+		return option.None[int]()
+	}
+
+	return option.Some(lhs / rhs)
+}
+
+func divideV3(lhs, rhs int) result.Result[int] {
+	if rhs == 0 {
+		return result.Err[int](errors.New("division by zero"))
+	}
+
+	return result.Ok(lhs / rhs)
+}
+
 func main() {
 	x := 100
 	y := 0
 
 	optional := divide(x, y)
 	fmt.Println(optional)
+
+	fmt.Println(divideV2(x, y).OrElse(-1))
+	fmt.Println(result.Map(divideV3(x, y), func(value int) int { return value * 2 }))
 }
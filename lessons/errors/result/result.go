@@ -0,0 +1,192 @@
+package result
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang_course/lessons/errors/option"
+)
+
+// Result holds either a value or an error.
+// The zero value is Ok with the zero value of T.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err panics on a nil error, since the result would silently become Ok.
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		panic("result: Err called with nil error")
+	}
+
+	return Result[T]{err: err}
+}
+
+// Of converts the (value, error) idiom.
+func Of[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+
+	return Ok(value)
+}
+
+// Try calls f and converts its result.
+func Try[T any](f func() (T, error)) Result[T] {
+	return Of(f())
+}
+
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Get returns the value in the (value, error) form.
+func (r Result[T]) Get() (T, error) {
+	if r.err != nil {
+		var zero T
+		return zero, r.err
+	}
+
+	return r.value, nil
+}
+
+// Unwrap returns the value and panics with the error when there is one.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+
+	return r.value
+}
+
+// Expect is Unwrap with a message added to the error.
+func (r Result[T]) Expect(message string) T {
+	if r.err != nil {
+		panic(fmt.Errorf("%s: %w", message, r.err))
+	}
+
+	return r.value
+}
+
+func (r Result[T]) OrElse(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+
+	return r.value
+}
+
+func (r Result[T]) OrElseFunc(fallback func(error) T) T {
+	if r.err != nil {
+		return fallback(r.err)
+	}
+
+	return r.value
+}
+
+// Option drops the error.
+func (r Result[T]) Option() option.Option[T] {
+	return option.FromPair(r.value, r.err == nil)
+}
+
+// MapErr replaces the error. When f returns nil the error is recovered
+// and the result becomes Ok with the zero value of T.
+func (r Result[T]) MapErr(f func(error) error) Result[T] {
+	if r.err == nil {
+		return r
+	}
+
+	return Result[T]{err: f(r.err)}
+}
+
+func (r Result[T]) String() string {
+	if r.err != nil {
+		return fmt.Sprintf("Err(%v)", r.err)
+	}
+
+	return fmt.Sprintf("Ok(%v)", r.value)
+}
+
+// FromOption turns None into Err(err), or into Ok with the zero value
+// of T when err is nil, the same way Of treats a nil error.
+func FromOption[T any](o option.Option[T], err error) Result[T] {
+	value, ok := o.Get()
+	if ok {
+		return Ok(value)
+	}
+
+	return Of(value, err)
+}
+
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+
+	return Ok(f(r.value))
+}
+
+func FlatMap[T, U any](r Result[T], f func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+
+	return f(r.value)
+}
+
+// FlatMapPair is FlatMap for functions written in the (value, error) style.
+func FlatMapPair[T, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+
+	return Of(f(r.value))
+}
+
+type jsonResult[T any] struct {
+	Value *T      `json:"value,omitempty"`
+	Error *string `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes {"value": ...} or {"error": "..."}.
+// Only the message of the error survives the round trip.
+func (r Result[T]) MarshalJSON() ([]byte, error) {
+	if r.err != nil {
+		message := r.err.Error()
+		return json.Marshal(jsonResult[T]{Error: &message})
+	}
+
+	return json.Marshal(jsonResult[T]{Value: &r.value})
+}
+
+func (r *Result[T]) UnmarshalJSON(data []byte) error {
+	var decoded jsonResult[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	switch {
+	case decoded.Error != nil:
+		*r = Err[T](errors.New(*decoded.Error))
+	case decoded.Value != nil:
+		*r = Ok(*decoded.Value)
+	default:
+		*r = Ok(*new(T))
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package result
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/lessons/errors/option"
+)
+
+// go test -v -race .
+
+func TestOkAndErr(t *testing.T) {
+	ok := Ok(10)
+	value, err := ok.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, value)
+	assert.True(t, ok.IsOk())
+	assert.Equal(t, 10, ok.Unwrap())
+	assert.Equal(t, "Ok(10)", ok.String())
+
+	failed := Err[int](fs.ErrNotExist)
+	value, err = failed.Get()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Zero(t, value)
+	assert.True(t, failed.IsErr())
+	assert.Equal(t, 5, failed.OrElse(5))
+	assert.Equal(t, -1, failed.OrElseFunc(func(error) int { return -1 }))
+	assert.Equal(t, "Err(file does not exist)", failed.String())
+	assert.PanicsWithError(t, "file does not exist", func() { failed.Unwrap() })
+	assert.PanicsWithError(t, "read config: file does not exist", func() { failed.Expect("read config") })
+
+	assert.Panics(t, func() { Err[int](nil) })
+}
+
+func TestConversions(t *testing.T) {
+	assert.Equal(t, Ok(42), Of(strconv.Atoi("42")))
+	assert.True(t, Of(strconv.Atoi("forty two")).IsErr())
+	assert.True(t, Try(func() (int, error) { return 0, fs.ErrClosed }).IsErr())
+
+	assert.Equal(t, option.Some(1), Ok(1).Option())
+	assert.Equal(t, option.None[int](), Err[int](fs.ErrClosed).Option())
+
+	assert.Equal(t, Ok(1), FromOption(option.Some(1), fs.ErrNotExist))
+	assert.ErrorIs(t, FromOption(option.None[int](), fs.ErrNotExist).Err(), fs.ErrNotExist)
+	assert.Equal(t, Ok(0), FromOption(option.None[int](), nil))
+}
+
+func TestCombinators(t *testing.T) {
+	parse := func(text string) Result[int] {
+		return Of(strconv.Atoi(text))
+	}
+
+	assert.Equal(t, Ok("10"), Map(Ok(10), strconv.Itoa))
+	assert.ErrorIs(t, Map(Err[int](fs.ErrClosed), strconv.Itoa).Err(), fs.ErrClosed)
+
+	assert.Equal(t, Ok(42), FlatMap(Ok("42"), parse))
+	assert.True(t, FlatMap(Ok("forty two"), parse).IsErr())
+	assert.ErrorIs(t, FlatMap(Err[string](fs.ErrClosed), parse).Err(), fs.ErrClosed)
+
+	assert.Equal(t, Ok(42), FlatMapPair(Ok("42"), strconv.Atoi))
+
+	wrapped := Err[int](fs.ErrClosed).MapErr(func(err error) error {
+		return errors.Join(errors.New("wrapped"), err)
+	})
+	assert.ErrorIs(t, wrapped.Err(), fs.ErrClosed)
+	assert.Equal(t, Ok(1), Ok(1).MapErr(func(error) error { return fs.ErrClosed }))
+	assert.Equal(t, Ok(0), Err[int](fs.ErrClosed).MapErr(func(error) error { return nil }))
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(Ok(42))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":42}`, string(data))
+
+	data, err = json.Marshal(Err[int](fs.ErrNotExist))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"error":"file does not exist"}`, string(data))
+
+	var decoded Result[int]
+	require.NoError(t, json.Unmarshal([]byte(`{"value":42}`), &decoded))
+	assert.Equal(t, Ok(42), decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"error":"file does not exist"}`), &decoded))
+	assert.EqualError(t, decoded.Err(), "file does not exist")
+}
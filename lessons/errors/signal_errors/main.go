@@ -1,6 +1,10 @@
 package main
 
-import "database/sql"
+import (
+	"database/sql"
+
+	"golang_course/lessons/errors/taxonomy"
+)
 
 type Database interface {
 	Query(string) (string, error)
@@ -16,3 +20,17 @@ func RunQueyry(db Database, query string) {
 		// ok
 	}
 }
+
+func RunQueryWithTaxonomy(db Database, query string) {
+	_, err := db.Query(query)
+	switch {
+	case err == nil:
+		// ok
+	case taxonomy.IsNotFound(err):
+		// not found, even if the error was wrapped
+	case taxonomy.IsRetryable(err):
+		// temporary problem, can be retried
+	default:
+		// error from database
+	}
+}
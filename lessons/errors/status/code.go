@@ -0,0 +1,141 @@
+package status
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Code is a gRPC-like error category. Unlike C-style integer statuses
+// it travels inside an error, so it survives wrapping.
+type Code int
+
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+var codeNames = [...]string{
+	OK:                 "OK",
+	Canceled:           "CANCELED",
+	Unknown:            "UNKNOWN",
+	InvalidArgument:    "INVALID_ARGUMENT",
+	DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	NotFound:           "NOT_FOUND",
+	AlreadyExists:      "ALREADY_EXISTS",
+	PermissionDenied:   "PERMISSION_DENIED",
+	ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	FailedPrecondition: "FAILED_PRECONDITION",
+	Aborted:            "ABORTED",
+	OutOfRange:         "OUT_OF_RANGE",
+	Unimplemented:      "UNIMPLEMENTED",
+	Internal:           "INTERNAL",
+	Unavailable:        "UNAVAILABLE",
+	DataLoss:           "DATA_LOSS",
+	Unauthenticated:    "UNAUTHENTICATED",
+}
+
+var httpStatuses = [...]int{
+	OK:                 http.StatusOK,
+	Canceled:           499, // client closed request
+	Unknown:            http.StatusInternalServerError,
+	InvalidArgument:    http.StatusBadRequest,
+	DeadlineExceeded:   http.StatusGatewayTimeout,
+	NotFound:           http.StatusNotFound,
+	AlreadyExists:      http.StatusConflict,
+	PermissionDenied:   http.StatusForbidden,
+	ResourceExhausted:  http.StatusTooManyRequests,
+	FailedPrecondition: http.StatusBadRequest,
+	Aborted:            http.StatusConflict,
+	OutOfRange:         http.StatusBadRequest,
+	Unimplemented:      http.StatusNotImplemented,
+	Internal:           http.StatusInternalServerError,
+	Unavailable:        http.StatusServiceUnavailable,
+	DataLoss:           http.StatusInternalServerError,
+	Unauthenticated:    http.StatusUnauthorized,
+}
+
+// exitCodes follow sysexits.h where there is a matching code.
+var exitCodes = [...]int{
+	OK:                 0,
+	Canceled:           130, // as if interrupted by SIGINT
+	Unknown:            1,
+	InvalidArgument:    64, // EX_USAGE
+	DeadlineExceeded:   75, // EX_TEMPFAIL
+	NotFound:           66, // EX_NOINPUT
+	AlreadyExists:      73, // EX_CANTCREAT
+	PermissionDenied:   77, // EX_NOPERM
+	ResourceExhausted:  75, // EX_TEMPFAIL
+	FailedPrecondition: 78, // EX_CONFIG
+	Aborted:            75, // EX_TEMPFAIL
+	OutOfRange:         65, // EX_DATAERR
+	Unimplemented:      69, // EX_UNAVAILABLE
+	Internal:           70, // EX_SOFTWARE
+	Unavailable:        69, // EX_UNAVAILABLE
+	DataLoss:           74, // EX_IOERR
+	Unauthenticated:    77, // EX_NOPERM
+}
+
+func (c Code) valid() bool {
+	return c >= 0 && int(c) < len(codeNames)
+}
+
+func (c Code) String() string {
+	if !c.valid() {
+		return fmt.Sprintf("CODE(%d)", int(c))
+	}
+
+	return codeNames[c]
+}
+
+// HTTPStatus returns the response status for the code,
+// unknown codes are reported as 500.
+func (c Code) HTTPStatus() int {
+	if !c.valid() {
+		return http.StatusInternalServerError
+	}
+
+	return httpStatuses[c]
+}
+
+// ExitCode returns the process exit code for the code.
+func (c Code) ExitCode() int {
+	if !c.valid() {
+		return 1
+	}
+
+	return exitCodes[c]
+}
+
+func (c Code) MarshalText() ([]byte, error) {
+	if !c.valid() {
+		return nil, fmt.Errorf("invalid code %d", int(c))
+	}
+
+	return []byte(codeNames[c]), nil
+}
+
+func (c *Code) UnmarshalText(text []byte) error {
+	for code, name := range codeNames {
+		if name == string(text) {
+			*c = Code(code)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown code %q", text)
+}
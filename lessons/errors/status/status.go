@@ -0,0 +1,157 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// Error attaches a Code, a client-facing message and details to an error.
+// The cause is kept for errors.Is/As and logs, but isn't sent to clients.
+type Error struct {
+	Code    Code
+	Message string
+	Details map[string]any
+	cause   error
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func Newf(code Code, format string, args ...any) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Wrap attaches code to err. It returns nil when err is nil.
+func Wrap(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Code: code, Message: message, cause: err}
+}
+
+// WithDetail returns a copy of e with one more detail.
+func (e *Error) WithDetail(key string, value any) *Error {
+	details := make(map[string]any, len(e.Details)+1)
+	for k, v := range e.Details {
+		details[k] = v
+	}
+
+	details[key] = value
+
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+func (e *Error) Error() string {
+	message := e.Code.String()
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+
+	if e.cause != nil {
+		message += ": " + e.cause.Error()
+	}
+
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+type coder interface {
+	Code() Code
+}
+
+// CodeOf returns the code of the first *Error in the chain of err.
+// Errors from other packages can take part by implementing Code() Code.
+// Context errors are mapped to Canceled and DeadlineExceeded,
+// nil to OK and everything else to Unknown.
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+
+	var statusErr *Error
+	var codeErr coder
+
+	switch {
+	case errors.As(err, &statusErr):
+		return statusErr.Code
+	case errors.As(err, &codeErr):
+		return codeErr.Code()
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	default:
+		return Unknown
+	}
+}
+
+// Convert returns the status error from the chain of err, or a new one
+// with the code from CodeOf. Messages of foreign errors can leak internal
+// details, so they are replaced with the code name.
+func Convert(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var statusErr *Error
+	if errors.As(err, &statusErr) {
+		return statusErr
+	}
+
+	code := CodeOf(err)
+	return &Error{Code: code, Message: code.String(), cause: err}
+}
+
+type jsonError struct {
+	Code    Code           `json:"code"`
+	Message string         `json:"message,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{Code: e.Code, Message: e.Message, Details: e.Details})
+}
+
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var decoded jsonError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*e = Error{Code: decoded.Code, Message: decoded.Message, Details: decoded.Details}
+	return nil
+}
+
+// WriteJSON writes err as {"error": {...}} with the matching HTTP status.
+func WriteJSON(w http.ResponseWriter, err error) {
+	statusErr := Convert(err)
+	if statusErr == nil {
+		statusErr = New(OK, "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusErr.Code.HTTPStatus())
+	_ = json.NewEncoder(w).Encode(struct {
+		Error *Error `json:"error"`
+	}{Error: statusErr})
+}
+
+// Exit terminates the process with the exit code that matches err.
+func Exit(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	os.Exit(CodeOf(err).ExitCode())
+}
@@ -0,0 +1,139 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+type quotaError struct{}
+
+func (quotaError) Error() string { return "quota exceeded" }
+func (quotaError) Code() Code    { return ResourceExhausted }
+
+func TestCodeOf(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		code Code
+	}{
+		"nil":              {err: nil, code: OK},
+		"status error":     {err: New(NotFound, "user not found"), code: NotFound},
+		"wrapped":          {err: fmt.Errorf("load: %w", New(Unavailable, "")), code: Unavailable},
+		"joined":           {err: errors.Join(fs.ErrClosed, New(Aborted, "")), code: Aborted},
+		"outermost status": {err: Wrap(New(NotFound, ""), Internal, "lookup"), code: Internal},
+		"custom coder":     {err: fmt.Errorf("call: %w", quotaError{}), code: ResourceExhausted},
+		"canceled":         {err: fmt.Errorf("wait: %w", context.Canceled), code: Canceled},
+		"deadline":         {err: context.DeadlineExceeded, code: DeadlineExceeded},
+		"foreign":          {err: fs.ErrNotExist, code: Unknown},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.code, CodeOf(test.err))
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(nil, NotFound, "message"))
+
+	err := Wrap(fs.ErrNotExist, NotFound, "config")
+	assert.Equal(t, "NOT_FOUND: config: file does not exist", err.Error())
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestMappings(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, NotFound.HTTPStatus())
+	assert.Equal(t, http.StatusServiceUnavailable, Unavailable.HTTPStatus())
+	assert.Equal(t, http.StatusInternalServerError, Code(100).HTTPStatus())
+
+	assert.Equal(t, 0, OK.ExitCode())
+	assert.Equal(t, 64, InvalidArgument.ExitCode())
+	assert.Equal(t, 1, Code(-1).ExitCode())
+
+	assert.Equal(t, "INVALID_ARGUMENT", InvalidArgument.String())
+	assert.Equal(t, "CODE(100)", Code(100).String())
+
+	for code := OK; code <= Unauthenticated; code++ {
+		assert.NotZero(t, code.HTTPStatus(), code)
+		assert.NotEmpty(t, codeNames[code], code)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	assert.Nil(t, Convert(nil))
+
+	statusErr := New(NotFound, "user not found")
+	assert.Same(t, statusErr, Convert(fmt.Errorf("lookup: %w", statusErr)))
+
+	tests := map[string]struct {
+		err  error
+		code Code
+	}{
+		"unknown":  {err: errors.New("password=secret"), code: Unknown},
+		"canceled": {err: fmt.Errorf("wait: %w", context.Canceled), code: Canceled},
+		"deadline": {err: context.DeadlineExceeded, code: DeadlineExceeded},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			converted := Convert(test.err)
+			assert.Equal(t, test.code, converted.Code)
+			assert.Equal(t, test.code.String(), converted.Message)
+			assert.ErrorIs(t, converted, test.err)
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	err := New(InvalidArgument, "bad email").WithDetail("field", "email")
+
+	data, marshalErr := json.Marshal(err)
+	require.NoError(t, marshalErr)
+	assert.JSONEq(t, `{"code":"INVALID_ARGUMENT","message":"bad email","details":{"field":"email"}}`, string(data))
+
+	var decoded Error
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, InvalidArgument, decoded.Code)
+	assert.Equal(t, "bad email", decoded.Message)
+	assert.Equal(t, map[string]any{"field": "email"}, decoded.Details)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"code":"UNKNOWN_CODE"}`), &decoded))
+	_, marshalErr = json.Marshal(New(Code(100), ""))
+	assert.Error(t, marshalErr)
+}
+
+func TestWithDetailCopies(t *testing.T) {
+	base := New(NotFound, "missing")
+	first := base.WithDetail("id", 1)
+	second := first.WithDetail("kind", "user")
+
+	assert.Nil(t, base.Details)
+	assert.Len(t, first.Details, 1)
+	assert.Len(t, second.Details, 2)
+}
+
+func TestWriteJSON(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	WriteJSON(recorder, fmt.Errorf("handler: %w", New(PermissionDenied, "admins only")))
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":{"code":"PERMISSION_DENIED","message":"admins only"}}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	WriteJSON(recorder, errors.New("password=secret"))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "secret")
+}
@@ -0,0 +1,114 @@
+package taxonomy
+
+import "strings"
+
+// Class is a set of error categories.
+type Class uint8
+
+const (
+	Temporary Class = 1 << iota
+	Timeout
+	NotFound
+	Conflict
+	Permanent
+)
+
+var classNames = []struct {
+	class Class
+	name  string
+}{
+	{Temporary, "temporary"},
+	{Timeout, "timeout"},
+	{NotFound, "not_found"},
+	{Conflict, "conflict"},
+	{Permanent, "permanent"},
+}
+
+// Has reports whether c contains all classes of other.
+func (c Class) Has(other Class) bool {
+	return other != 0 && c&other == other
+}
+
+func (c Class) String() string {
+	if c == 0 {
+		return "unclassified"
+	}
+
+	var names []string
+	for _, entry := range classNames {
+		if c&entry.class != 0 {
+			names = append(names, entry.name)
+		}
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Behavior interfaces. Errors from any package are classified by them
+// without importing this package, like net.Error does for timeouts.
+
+type TemporaryError interface {
+	Temporary() bool
+}
+
+type TimeoutError interface {
+	Timeout() bool
+}
+
+type NotFoundError interface {
+	NotFound() bool
+}
+
+type ConflictError interface {
+	Conflict() bool
+}
+
+type PermanentError interface {
+	Permanent() bool
+}
+
+func behaviorOf(err error) Class {
+	var class Class
+	if e, ok := err.(TemporaryError); ok && e.Temporary() {
+		class |= Temporary
+	}
+
+	if e, ok := err.(TimeoutError); ok && e.Timeout() {
+		class |= Timeout
+	}
+
+	if e, ok := err.(NotFoundError); ok && e.NotFound() {
+		class |= NotFound
+	}
+
+	if e, ok := err.(ConflictError); ok && e.Conflict() {
+		class |= Conflict
+	}
+
+	if e, ok := err.(PermanentError); ok && e.Permanent() {
+		class |= Permanent
+	}
+
+	return class
+}
+
+type marked struct {
+	error
+	class Class
+}
+
+// Mark adds classes to err. It returns nil when err is nil.
+func Mark(err error, class Class) error {
+	if err == nil {
+		return nil
+	}
+
+	return &marked{error: err, class: class}
+}
+
+func (e *marked) Unwrap() error   { return e.error }
+func (e *marked) Temporary() bool { return e.class.Has(Temporary) }
+func (e *marked) Timeout() bool   { return e.class.Has(Timeout) }
+func (e *marked) NotFound() bool  { return e.class.Has(NotFound) }
+func (e *marked) Conflict() bool  { return e.class.Has(Conflict) }
+func (e *marked) Permanent() bool { return e.class.Has(Permanent) }
@@ -0,0 +1,135 @@
+package taxonomy
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"sync"
+)
+
+// Classifier is called for every error in the wrap tree and returns
+// the classes it recognizes, or zero.
+type Classifier func(err error) Class
+
+type sentinel struct {
+	target error
+	class  Class
+}
+
+// Registry keeps classifiers for errors that don't implement
+// the behavior interfaces, mostly sentinels of third-party packages.
+type Registry struct {
+	mutex       sync.RWMutex
+	sentinels   []sentinel
+	classifiers []Classifier
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// RegisterSentinel classifies errors that are equal to target
+// or match it through an Is method.
+func (r *Registry) RegisterSentinel(target error, class Class) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sentinels = append(r.sentinels, sentinel{target: target, class: class})
+}
+
+func (r *Registry) Register(classifier Classifier) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.classifiers = append(r.classifiers, classifier)
+}
+
+// Classify returns the union of classes of every error in the tree of err,
+// following both Unwrap() error and Unwrap() []error.
+func (r *Registry) Classify(err error) Class {
+	if err == nil {
+		return 0
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var class Class
+	walk(err, func(node error) {
+		class |= behaviorOf(node)
+		for _, s := range r.sentinels {
+			if matches(node, s.target) {
+				class |= s.class
+			}
+		}
+
+		for _, classifier := range r.classifiers {
+			class |= classifier(node)
+		}
+	})
+
+	return class
+}
+
+func walk(err error, visit func(error)) {
+	for err != nil {
+		visit(err)
+
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			err = wrapped.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, child := range wrapped.Unwrap() {
+				walk(child, visit)
+			}
+
+			return
+		default:
+			return
+		}
+	}
+}
+
+func matches(err, target error) bool {
+	if err == target {
+		return true
+	}
+
+	matcher, ok := err.(interface{ Is(error) bool })
+	return ok && matcher.Is(target)
+}
+
+// DefaultRegistry knows the sentinels of the standard library.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.RegisterSentinel(sql.ErrNoRows, NotFound)
+	DefaultRegistry.RegisterSentinel(fs.ErrNotExist, NotFound)
+	DefaultRegistry.RegisterSentinel(fs.ErrExist, Conflict)
+	DefaultRegistry.RegisterSentinel(fs.ErrPermission, Permanent)
+	DefaultRegistry.RegisterSentinel(context.Canceled, Permanent)
+}
+
+func RegisterSentinel(target error, class Class) {
+	DefaultRegistry.RegisterSentinel(target, class)
+}
+
+func Register(classifier Classifier) {
+	DefaultRegistry.Register(classifier)
+}
+
+func Classify(err error) Class {
+	return DefaultRegistry.Classify(err)
+}
+
+func IsTemporary(err error) bool { return Classify(err).Has(Temporary) }
+func IsTimeout(err error) bool   { return Classify(err).Has(Timeout) }
+func IsNotFound(err error) bool  { return Classify(err).Has(NotFound) }
+func IsConflict(err error) bool  { return Classify(err).Has(Conflict) }
+
+// IsRetryable reports whether err is temporary or a timeout
+// and nothing in its tree marks it as permanent.
+func IsRetryable(err error) bool {
+	class := Classify(err)
+	return class&(Temporary|Timeout) != 0 && !class.Has(Permanent)
+}
@@ -0,0 +1,121 @@
+package taxonomy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v -race .
+
+type lockError struct{}
+
+func (lockError) Error() string   { return "row is locked" }
+func (lockError) Conflict() bool  { return true }
+func (lockError) Temporary() bool { return true }
+
+var errThirdParty = errors.New("third party: try again later")
+
+func TestClassify(t *testing.T) {
+	tests := map[string]struct {
+		err   error
+		class Class
+	}{
+		"nil":              {err: nil, class: 0},
+		"plain":            {err: errors.New("plain"), class: 0},
+		"behavior":         {err: fmt.Errorf("update: %w", lockError{}), class: Conflict | Temporary},
+		"sentinel":         {err: fmt.Errorf("select: %w", sql.ErrNoRows), class: NotFound},
+		"sentinel with Is": {err: &os.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}, class: NotFound},
+		"deadline":         {err: fmt.Errorf("call: %w", context.DeadlineExceeded), class: Timeout | Temporary},
+		"marked":           {err: Mark(errors.New("quota"), Temporary), class: Temporary},
+		"joined": {
+			err:   errors.Join(errors.New("first"), fmt.Errorf("second: %w", Mark(sql.ErrNoRows, Permanent))),
+			class: NotFound | Permanent,
+		},
+		"nested joined": {
+			err:   fmt.Errorf("batch: %w", errors.Join(lockError{}, errors.Join(os.ErrExist))),
+			class: Conflict | Temporary,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.class, Classify(test.err), Classify(test.err).String())
+		})
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	assert.True(t, IsNotFound(fmt.Errorf("get: %w", sql.ErrNoRows)))
+	assert.True(t, IsConflict(lockError{}))
+	assert.True(t, IsTimeout(os.ErrDeadlineExceeded))
+	assert.True(t, IsTemporary(lockError{}))
+
+	assert.True(t, IsRetryable(lockError{}))
+	assert.False(t, IsRetryable(Mark(lockError{}, Permanent)))
+	assert.False(t, IsRetryable(errors.New("plain")))
+	assert.False(t, IsRetryable(nil))
+}
+
+func TestMark(t *testing.T) {
+	assert.Nil(t, Mark(nil, Temporary))
+
+	err := Mark(sql.ErrNoRows, Temporary|Conflict)
+	assert.Equal(t, sql.ErrNoRows.Error(), err.Error())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	assert.Equal(t, Class(0), registry.Classify(sql.ErrNoRows))
+
+	registry.RegisterSentinel(errThirdParty, Temporary)
+	registry.Register(func(err error) Class {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return Conflict
+		}
+
+		return 0
+	})
+
+	assert.Equal(t, Temporary, registry.Classify(fmt.Errorf("call: %w", errThirdParty)))
+	assert.Equal(t, Conflict, registry.Classify(errors.New("insert: duplicate key value")))
+	assert.Equal(t, Class(0), Classify(errThirdParty))
+}
+
+func TestRegistryConcurrency(t *testing.T) {
+	registry := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registry.RegisterSentinel(errThirdParty, Temporary)
+		}()
+
+		go func() {
+			defer wg.Done()
+			_ = registry.Classify(errThirdParty)
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, Temporary, registry.Classify(errThirdParty))
+}
+
+func TestClassString(t *testing.T) {
+	assert.Equal(t, "unclassified", Class(0).String())
+	assert.Equal(t, "temporary|not_found", (NotFound | Temporary).String())
+	assert.True(t, (NotFound | Temporary).Has(NotFound))
+	assert.False(t, NotFound.Has(NotFound|Temporary))
+	assert.False(t, NotFound.Has(0))
+}
@@ -0,0 +1,121 @@
+package safe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"runtime/debug"
+)
+
+// ErrGoexit is reported by Go when the function called runtime.Goexit.
+var ErrGoexit = errors.New("runtime.Goexit was called")
+
+// PanicError is a recovered panic. Stack is captured at the panic site,
+// so it still points to the original code after the error is passed around.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", p.Value, p.Stack)
+}
+
+func (p *PanicError) Unwrap() error {
+	err, ok := p.Value.(error)
+	if !ok {
+		return nil
+	}
+
+	return err
+}
+
+// Repanic raises the panic again. Call and Go recognize the value,
+// so the original stack is kept instead of the stack of Repanic.
+func (p *PanicError) Repanic() {
+	panic(p)
+}
+
+func newPanicError(value any) *PanicError {
+	if p, ok := value.(*PanicError); ok {
+		return p
+	}
+
+	stack := debug.Stack()
+
+	// The first line of the stack is "goroutine N [running]:",
+	// it is meaningless once the error leaves the goroutine.
+	if line := bytes.IndexByte(stack, '\n'); line >= 0 {
+		stack = stack[line+1:]
+	}
+
+	return &PanicError{Value: value, Stack: stack}
+}
+
+// call doesn't return when fn calls runtime.Goexit: Goexit can't be
+// stopped, so the deferred functions of the caller run and its goroutine exits.
+func call(fn func()) (err *PanicError) {
+	normalReturn := false
+	func() {
+		defer func() {
+			if normalReturn {
+				return
+			}
+
+			// recover returns nil for panic(nil) with GODEBUG=panicnil=1,
+			// but the panic is stopped anyway, so it is reported as
+			// the error that newer runtimes use.
+			value := recover()
+			if value == nil {
+				value = new(runtime.PanicNilError)
+			}
+
+			err = newPanicError(value)
+		}()
+
+		fn()
+		normalReturn = true
+	}()
+
+	return err
+}
+
+// Call runs fn and returns a *PanicError if it panics.
+// If fn calls runtime.Goexit, the current goroutine exits as usual.
+func Call(fn func()) error {
+	if err := call(fn); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Go runs fn in a new goroutine. A panic is passed to onPanic as
+// a *PanicError and runtime.Goexit as ErrGoexit. A nil onPanic logs
+// the error.
+func Go(fn func(), onPanic func(error)) {
+	if onPanic == nil {
+		onPanic = func(err error) {
+			log.Printf("safe.Go: %v", err)
+		}
+	}
+
+	go run(fn, onPanic)
+}
+
+func run(fn func(), onPanic func(error)) {
+	finished := false
+	defer func() {
+		if !finished {
+			onPanic(ErrGoexit)
+		}
+	}()
+
+	err := call(fn)
+	finished = true
+	if err != nil {
+		onPanic(err)
+	}
+}
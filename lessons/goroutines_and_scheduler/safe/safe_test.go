@@ -0,0 +1,133 @@
+package safe
+
+import (
+	"io/fs"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+func panicSite() {
+	panic(fs.ErrClosed)
+}
+
+func TestCall(t *testing.T) {
+	called := false
+	assert.NoError(t, Call(func() { called = true }))
+	assert.True(t, called)
+
+	err := Call(func() { panic("boom") })
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.True(t, strings.HasPrefix(err.Error(), "panic: boom\n\n"))
+	assert.False(t, strings.HasPrefix(string(panicErr.Stack), "goroutine "))
+}
+
+func TestCallWithErrorValue(t *testing.T) {
+	err := Call(panicSite)
+
+	assert.ErrorIs(t, err, fs.ErrClosed)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Contains(t, string(panicErr.Stack), "safe.panicSite")
+}
+
+func TestCallWithNilPanic(t *testing.T) {
+	err := Call(func() { panic(nil) })
+
+	var panicNil *runtime.PanicNilError
+	assert.ErrorAs(t, err, &panicNil)
+}
+
+func TestRepanicKeepsOriginalStack(t *testing.T) {
+	original := Call(panicSite)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, original, &panicErr)
+
+	err := Call(func() {
+		panicErr.Repanic()
+	})
+
+	assert.Same(t, original, err)
+}
+
+func TestCallWithGoexit(t *testing.T) {
+	returned := false
+	deferred := false
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() { deferred = true }()
+
+		_ = Call(runtime.Goexit)
+		returned = true
+	}()
+
+	<-done
+	assert.True(t, deferred)
+	assert.False(t, returned)
+}
+
+func TestGo(t *testing.T) {
+	tests := map[string]struct {
+		fn    func()
+		check func(t *testing.T, err error)
+	}{
+		"no panic": {
+			fn: func() {},
+			check: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		"panic": {
+			fn: panicSite,
+			check: func(t *testing.T, err error) {
+				var panicErr *PanicError
+				assert.ErrorAs(t, err, &panicErr)
+				assert.ErrorIs(t, err, fs.ErrClosed)
+			},
+		},
+		"goexit": {
+			fn: runtime.Goexit,
+			check: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrGoexit)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			reported := make(chan error, 1)
+			Go(func() {
+				test.fn()
+				reported <- nil
+			}, func(err error) {
+				reported <- err
+			})
+
+			test.check(t, <-reported)
+		})
+	}
+}
+
+func TestPanicInHandlerIsNotReportedAsGoexit(t *testing.T) {
+	var calls []error
+	onPanic := func(err error) {
+		calls = append(calls, err)
+		panic("handler failed")
+	}
+
+	assert.Panics(t, func() { run(panicSite, onPanic) })
+	require.Len(t, calls, 1)
+	assert.ErrorIs(t, calls[0], fs.ErrClosed)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net"
+
+	"golang_course/lessons/goroutines_and_scheduler/safe"
+)
+
+// nc 127.0.0.1 12345
+
+func main() {
+	listener, err := net.Listen("tcp", ":12345")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		safe.Go(func() {
+			ClientHandler(conn)
+		}, func(err error) {
+			_ = conn.Close()
+			log.Println("client handler failed:", err)
+		})
+	}
+}
+
+func ClientHandler(c net.Conn) {
+	panic(errors.New("internal error"))
+}
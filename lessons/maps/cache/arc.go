@@ -0,0 +1,158 @@
+package cache
+
+// arc is the Adaptive Replacement Cache by Megiddo and Modha. Entries
+// seen once live in recent, entries seen again in frequent. The ghost
+// lists remember keys evicted from each of them and move the target
+// size of recent towards the list that would have given a hit, so a
+// one-time scan doesn't wash out frequently used entries.
+type arc[K comparable, V any] struct {
+	capacity int
+	target   int // of the recent list
+
+	recent   list[*entry[K, V]]
+	frequent list[*entry[K, V]]
+	resident map[K]*arcItem[K, V]
+
+	recentGhosts   list[K]
+	frequentGhosts list[K]
+	ghosts         map[K]*arcGhost[K]
+}
+
+type arcItem[K comparable, V any] struct {
+	node     *node[*entry[K, V]]
+	frequent bool
+}
+
+type arcGhost[K comparable] struct {
+	node     *node[K]
+	frequent bool
+}
+
+func newARC[K comparable, V any](capacity int) *arc[K, V] {
+	return &arc[K, V]{
+		capacity: capacity,
+		resident: make(map[K]*arcItem[K, V], capacity),
+		ghosts:   make(map[K]*arcGhost[K], capacity),
+	}
+}
+
+func (c *arc[K, V]) get(key K) (*entry[K, V], bool) {
+	item, found := c.resident[key]
+	if !found {
+		return nil, false
+	}
+
+	if item.frequent {
+		c.frequent.moveToFront(item.node)
+	} else {
+		c.recent.remove(item.node)
+		c.frequent.pushNodeFront(item.node)
+		item.frequent = true
+	}
+
+	return item.node.value, true
+}
+
+func (c *arc[K, V]) add(e *entry[K, V]) []*entry[K, V] {
+	var evicted []*entry[K, V]
+
+	if ghost, found := c.ghosts[e.key]; found {
+		recentGhosts, frequentGhosts := c.recentGhosts.len(), c.frequentGhosts.len()
+		if ghost.frequent {
+			c.target = max(0, c.target-max(recentGhosts/frequentGhosts, 1))
+			c.frequentGhosts.remove(ghost.node)
+		} else {
+			c.target = min(c.capacity, c.target+max(frequentGhosts/recentGhosts, 1))
+			c.recentGhosts.remove(ghost.node)
+		}
+
+		delete(c.ghosts, e.key)
+		if len(c.resident) >= c.capacity {
+			evicted = append(evicted, c.replace(ghost.frequent))
+		}
+
+		c.resident[e.key] = &arcItem[K, V]{node: c.frequent.pushFront(e), frequent: true}
+		return evicted
+	}
+
+	if c.recent.len()+c.recentGhosts.len() >= c.capacity {
+		if c.recent.len() < c.capacity {
+			c.dropGhost(&c.recentGhosts)
+			if len(c.resident) >= c.capacity {
+				evicted = append(evicted, c.replace(false))
+			}
+		} else {
+			oldest := c.recent.back()
+			c.recent.remove(oldest)
+			delete(c.resident, oldest.value.key)
+			evicted = append(evicted, oldest.value)
+		}
+	} else if len(c.resident)+len(c.ghosts) >= c.capacity {
+		if len(c.resident)+len(c.ghosts) >= 2*c.capacity {
+			c.dropGhost(&c.frequentGhosts)
+		}
+
+		if len(c.resident) >= c.capacity {
+			evicted = append(evicted, c.replace(false))
+		}
+	}
+
+	c.resident[e.key] = &arcItem[K, V]{node: c.recent.pushFront(e)}
+	return evicted
+}
+
+// replace evicts an entry from recent or frequent and remembers its key.
+func (c *arc[K, V]) replace(frequentGhostHit bool) *entry[K, V] {
+	recentSize := c.recent.len()
+	fromRecent := recentSize > 0 && (recentSize > c.target || (frequentGhostHit && recentSize == c.target))
+	if c.frequent.len() == 0 {
+		fromRecent = true
+	}
+
+	source, ghosts := &c.frequent, &c.frequentGhosts
+	if fromRecent {
+		source, ghosts = &c.recent, &c.recentGhosts
+	}
+
+	oldest := source.back()
+	source.remove(oldest)
+	delete(c.resident, oldest.value.key)
+
+	c.ghosts[oldest.value.key] = &arcGhost[K]{node: ghosts.pushFront(oldest.value.key), frequent: !fromRecent}
+	return oldest.value
+}
+
+func (c *arc[K, V]) dropGhost(ghosts *list[K]) {
+	oldest := ghosts.back()
+	if oldest == nil {
+		return
+	}
+
+	ghosts.remove(oldest)
+	delete(c.ghosts, oldest.value)
+}
+
+func (c *arc[K, V]) remove(key K) (*entry[K, V], bool) {
+	item, found := c.resident[key]
+	if !found {
+		return nil, false
+	}
+
+	if item.frequent {
+		c.frequent.remove(item.node)
+	} else {
+		c.recent.remove(item.node)
+	}
+
+	delete(c.resident, key)
+	return item.node.value, true
+}
+
+func (c *arc[K, V]) len() int {
+	return len(c.resident)
+}
+
+func (c *arc[K, V]) each(action func(*entry[K, V])) {
+	c.recent.each(action)
+	c.frequent.each(action)
+}
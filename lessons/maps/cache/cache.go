@@ -0,0 +1,331 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type Policy int
+
+const (
+	LRU Policy = iota
+	LFU
+	ARC
+)
+
+func (p Policy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case ARC:
+		return "ARC"
+	default:
+		return "unknown"
+	}
+}
+
+type EvictionReason int
+
+const (
+	Evicted EvictionReason = iota // to free space for a new entry
+	Expired
+	Deleted
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case Evicted:
+		return "evicted"
+	case Expired:
+		return "expired"
+	case Deleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s Stats) add(other Stats) Stats {
+	return Stats{
+		Hits:        s.Hits + other.Hits,
+		Misses:      s.Misses + other.Misses,
+		Evictions:   s.Evictions + other.Evictions,
+		Expirations: s.Expirations + other.Expirations,
+	}
+}
+
+type Config[K comparable, V any] struct {
+	Capacity int
+	Policy   Policy
+
+	// TTL is the default time to live, zero means entries don't expire.
+	// Expired entries are removed lazily on access, and also in the
+	// background when CleanupInterval is set.
+	TTL             time.Duration
+	CleanupInterval time.Duration
+
+	// OnEvict is called without the lock held, so it can use the cache.
+	OnEvict func(key K, value V, reason EvictionReason)
+
+	Clock Clock
+
+	// Shards is used by NewSharded, zero means GOMAXPROCS.
+	// It is limited to Capacity, so every shard holds at least one entry.
+	Shards int
+}
+
+type policy[K comparable, V any] interface {
+	get(key K) (*entry[K, V], bool)
+	add(e *entry[K, V]) []*entry[K, V] // the key must be absent
+	remove(key K) (*entry[K, V], bool)
+	len() int
+	each(action func(*entry[K, V]))
+}
+
+type eviction[K comparable, V any] struct {
+	entry  *entry[K, V]
+	reason EvictionReason
+}
+
+// Cache is safe for concurrent use. Every access changes the eviction
+// order, so it is guarded by a single mutex. Use Sharded under contention.
+type Cache[K comparable, V any] struct {
+	mutex   sync.Mutex
+	policy  policy[K, V]
+	expiry  expiryHeap[K, V]
+	stats   Stats
+	ttl     time.Duration
+	onEvict func(K, V, EvictionReason)
+	clock   Clock
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func New[K comparable, V any](config Config[K, V]) *Cache[K, V] {
+	c := newCache(config)
+	if config.CleanupInterval > 0 {
+		c.stop = make(chan struct{})
+		go runCleanup(config.CleanupInterval, c.stop, c.DeleteExpired)
+	}
+
+	return c
+}
+
+func newCache[K comparable, V any](config Config[K, V]) *Cache[K, V] {
+	if config.Capacity <= 0 {
+		panic("capacity must be positive")
+	}
+
+	c := &Cache[K, V]{
+		ttl:     config.TTL,
+		onEvict: config.OnEvict,
+		clock:   config.Clock,
+	}
+
+	if c.clock == nil {
+		c.clock = realClock{}
+	}
+
+	switch config.Policy {
+	case LFU:
+		c.policy = newLFU[K, V](config.Capacity)
+	case ARC:
+		c.policy = newARC[K, V](config.Capacity)
+	default:
+		c.policy = newLRU[K, V](config.Capacity)
+	}
+
+	return c
+}
+
+func runCleanup(interval time.Duration, stop <-chan struct{}, cleanup func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cleanup()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close stops the background cleanup.
+func (c *Cache[K, V]) Close() {
+	c.once.Do(func() {
+		if c.stop != nil {
+			close(c.stop)
+		}
+	})
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mutex.Lock()
+
+	var evictions []eviction[K, V]
+	e, found := c.policy.get(key)
+	if found && e.expired(c.clock.Now()) {
+		evictions = append(evictions, c.expireLocked(e))
+		found = false
+	}
+
+	var value V
+	if found {
+		c.stats.Hits++
+		value = e.value
+	} else {
+		c.stats.Misses++
+	}
+
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+	return value, found
+}
+
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL overrides the default TTL for one entry, zero means no expiration.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.clock.Now().Add(ttl)
+	}
+
+	c.mutex.Lock()
+
+	var evictions []eviction[K, V]
+	if e, found := c.policy.get(key); found {
+		e.value = value
+		e.expiresAt = expiresAt
+		c.expiry.update(e)
+	} else {
+		e := &entry[K, V]{key: key, value: value, expiresAt: expiresAt, heapIdx: -1}
+		for _, evicted := range c.policy.add(e) {
+			c.expiry.remove(evicted)
+			c.stats.Evictions++
+			evictions = append(evictions, eviction[K, V]{entry: evicted, reason: Evicted})
+		}
+
+		c.expiry.update(e)
+	}
+
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mutex.Lock()
+
+	e, found := c.policy.remove(key)
+	var evictions []eviction[K, V]
+	if found {
+		c.expiry.remove(e)
+		evictions = append(evictions, eviction[K, V]{entry: e, reason: Deleted})
+	}
+
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+	return found
+}
+
+// DeleteExpired removes expired entries, it is called periodically
+// when CleanupInterval is set.
+func (c *Cache[K, V]) DeleteExpired() {
+	c.mutex.Lock()
+
+	var evictions []eviction[K, V]
+	now := c.clock.Now()
+	for e := c.expiry.peek(); e != nil && e.expired(now); e = c.expiry.peek() {
+		evictions = append(evictions, c.expireLocked(e))
+	}
+
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+}
+
+// Purge removes all entries, OnEvict is called with Deleted for each of them.
+func (c *Cache[K, V]) Purge() {
+	c.mutex.Lock()
+
+	var entries []*entry[K, V]
+	c.policy.each(func(e *entry[K, V]) {
+		entries = append(entries, e)
+	})
+
+	evictions := make([]eviction[K, V], 0, len(entries))
+	for _, e := range entries {
+		c.policy.remove(e.key)
+		c.expiry.remove(e)
+		evictions = append(evictions, eviction[K, V]{entry: e, reason: Deleted})
+	}
+
+	c.mutex.Unlock()
+
+	c.notify(evictions)
+}
+
+// Len may include expired entries that weren't removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.policy.len()
+}
+
+func (c *Cache[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stats
+}
+
+func (c *Cache[K, V]) expireLocked(e *entry[K, V]) eviction[K, V] {
+	c.stats.Expirations++
+	c.policy.remove(e.key)
+	c.expiry.remove(e)
+	return eviction[K, V]{entry: e, reason: Expired}
+}
+
+func (c *Cache[K, V]) notify(evictions []eviction[K, V]) {
+	if c.onEvict == nil {
+		return
+	}
+
+	for _, evicted := range evictions {
+		c.onEvict(evicted.entry.key, evicted.entry.value, evicted.reason)
+	}
+}
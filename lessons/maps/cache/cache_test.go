@@ -0,0 +1,431 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+type evictionLog struct {
+	mutex  sync.Mutex
+	keys   []int
+	reason []EvictionReason
+}
+
+func (l *evictionLog) onEvict(key int, _ string, reason EvictionReason) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.keys = append(l.keys, key)
+	l.reason = append(l.reason, reason)
+}
+
+func keys[K comparable, V any](c *Cache[K, V]) []K {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var result []K
+	c.policy.each(func(e *entry[K, V]) {
+		result = append(result, e.key)
+	})
+
+	return result
+}
+
+func TestCommonBehavior(t *testing.T) {
+	for _, policy := range []Policy{LRU, LFU, ARC} {
+		t.Run(policy.String(), func(t *testing.T) {
+			c := New(Config[int, string]{Capacity: 3, Policy: policy})
+
+			_, found := c.Get(1)
+			assert.False(t, found)
+
+			c.Set(1, "one")
+			c.Set(2, "two")
+			c.Set(2, "second")
+
+			value, found := c.Get(2)
+			assert.True(t, found)
+			assert.Equal(t, "second", value)
+			assert.Equal(t, 2, c.Len())
+
+			for i := 3; i < 10; i++ {
+				c.Set(i, fmt.Sprint(i))
+				assert.LessOrEqual(t, c.Len(), 3)
+			}
+
+			assert.True(t, c.Delete(9))
+			assert.False(t, c.Delete(9))
+			assert.Equal(t, 2, c.Len())
+
+			c.Purge()
+			assert.Zero(t, c.Len())
+
+			stats := c.Stats()
+			assert.Equal(t, uint64(1), stats.Hits)
+			assert.Equal(t, uint64(1), stats.Misses)
+			assert.Equal(t, uint64(6), stats.Evictions)
+		})
+	}
+}
+
+func TestLRU(t *testing.T) {
+	var log evictionLog
+	c := New(Config[int, string]{Capacity: 3, OnEvict: log.onEvict})
+
+	c.Set(1, "one")
+	c.Set(2, "two")
+	c.Set(3, "three")
+	c.Get(1)
+	c.Set(4, "four")
+	c.Set(5, "five")
+
+	assert.Equal(t, []int{5, 4, 1}, keys(c))
+	assert.Equal(t, []int{2, 3}, log.keys)
+	assert.Equal(t, []EvictionReason{Evicted, Evicted}, log.reason)
+}
+
+func TestLFU(t *testing.T) {
+	var log evictionLog
+	c := New(Config[int, string]{Capacity: 3, Policy: LFU, OnEvict: log.onEvict})
+
+	c.Set(1, "one")
+	c.Set(2, "two")
+	c.Set(3, "three")
+	for range 3 {
+		c.Get(1)
+	}
+
+	c.Get(2)
+	c.Set(4, "four") // 3 has the lowest frequency
+	c.Set(5, "five") // 4 has the same frequency as 5, but it is older
+
+	assert.Equal(t, []int{3, 4}, log.keys)
+
+	c.Get(5)
+	c.Get(5)
+	c.Set(6, "six") // frequencies are 1: 4, 2: 3, 5: 3, so 2 is evicted
+	assert.Equal(t, []int{3, 4, 2}, log.keys)
+}
+
+func TestLFUFrequencyBuckets(t *testing.T) {
+	c := newLFU[int, int](10)
+	for i := range 5 {
+		c.add(&entry[int, int]{key: i})
+		for range i {
+			c.get(i)
+		}
+	}
+
+	var frequencies []int
+	c.buckets.each(func(bucket *lfuBucket[int, int]) {
+		frequencies = append(frequencies, bucket.frequency)
+		assert.Equal(t, 1, bucket.items.len())
+	})
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, frequencies)
+
+	c.remove(2)
+	c.get(1)
+	c.get(1)
+
+	frequencies = frequencies[:0]
+	c.buckets.each(func(bucket *lfuBucket[int, int]) {
+		frequencies = append(frequencies, bucket.frequency)
+	})
+
+	assert.Equal(t, []int{1, 4, 5}, frequencies)
+}
+
+func TestARCScanResistance(t *testing.T) {
+	const capacity = 100
+
+	hitRatio := func(policy Policy) float64 {
+		c := New(Config[int, string]{Capacity: capacity, Policy: policy})
+		hot := func() {
+			for key := range capacity / 2 {
+				c.Get(key)
+				c.Set(key, "hot")
+			}
+		}
+
+		hot()
+		hot()
+		for i := range 10 {
+			for key := range capacity {
+				c.Set(1000+i*capacity+key, "scan")
+			}
+
+			hot()
+		}
+
+		return c.Stats().HitRatio()
+	}
+
+	lru, arc := hitRatio(LRU), hitRatio(ARC)
+	assert.Greater(t, arc, lru)
+	assert.Greater(t, arc, 0.8)
+}
+
+func TestARCAdaptation(t *testing.T) {
+	c := newARC[int, int](4)
+	add := func(key int) {
+		if _, found := c.get(key); !found {
+			c.add(&entry[int, int]{key: key})
+		}
+	}
+
+	for key := range 4 {
+		add(key)
+	}
+
+	add(0)
+	add(1)
+	add(4)
+	add(5)
+
+	assert.Equal(t, 2, c.recent.len())
+	assert.Equal(t, 2, c.frequent.len())
+	assert.Equal(t, 2, c.recentGhosts.len())
+	assert.Zero(t, c.target)
+
+	add(2) // ghost hit in recent makes the target larger
+	assert.Equal(t, 1, c.target)
+	assert.Equal(t, 3, c.frequent.len())
+	assert.Equal(t, 2, c.recentGhosts.len())
+	assert.LessOrEqual(t, len(c.resident), 4)
+	assert.LessOrEqual(t, len(c.resident)+len(c.ghosts), 8)
+
+	for key := range 100 {
+		add(key % 13)
+		assert.LessOrEqual(t, len(c.resident), 4)
+		assert.LessOrEqual(t, len(c.resident)+len(c.ghosts), 8)
+		assert.Equal(t, len(c.resident), c.recent.len()+c.frequent.len())
+		assert.Equal(t, len(c.ghosts), c.recentGhosts.len()+c.frequentGhosts.len())
+	}
+}
+
+func TestTTL(t *testing.T) {
+	var log evictionLog
+	clock := newFakeClock()
+	c := New(Config[int, string]{
+		Capacity: 10,
+		TTL:      time.Minute,
+		Clock:    clock,
+		OnEvict:  log.onEvict,
+	})
+
+	c.Set(1, "one")
+	c.SetWithTTL(2, "two", time.Hour)
+	c.SetWithTTL(3, "three", 0)
+
+	clock.Advance(time.Minute - time.Second)
+	_, found := c.Get(1)
+	assert.True(t, found)
+
+	clock.Advance(time.Second)
+	_, found = c.Get(1)
+	assert.False(t, found)
+	assert.Equal(t, []int{1}, log.keys)
+	assert.Equal(t, []EvictionReason{Expired}, log.reason)
+
+	c.Set(2, "two") // resets the TTL to the default one
+	clock.Advance(2 * time.Minute)
+	c.DeleteExpired()
+
+	assert.Equal(t, []int{1, 2}, log.keys)
+	assert.Equal(t, []int{3}, keys(c))
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(2), stats.Expirations)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Zero(t, stats.Evictions)
+}
+
+func TestExpiryHeap(t *testing.T) {
+	clock := newFakeClock()
+	c := New(Config[int, int]{Capacity: 100, Clock: clock})
+
+	for i := range 50 {
+		c.SetWithTTL(i, i, time.Duration(50-i)*time.Second)
+	}
+
+	for i := 0; i < 50; i += 2 {
+		c.Delete(i)
+	}
+
+	require.Len(t, c.expiry, 25)
+	for i, e := range c.expiry {
+		assert.Equal(t, i, e.heapIdx)
+	}
+
+	clock.Advance(25 * time.Second)
+	c.DeleteExpired()
+
+	assert.Len(t, c.expiry, 12)
+	assert.Equal(t, 12, c.Len())
+	for _, key := range keys(c) {
+		assert.Equal(t, 1, key%2)
+		assert.Less(t, key, 25)
+	}
+}
+
+func TestExpiredEntriesAreEvictedWithoutTTL(t *testing.T) {
+	c := New(Config[int, int]{Capacity: 2, TTL: time.Minute, Clock: newFakeClock()})
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+
+	assert.Len(t, c.expiry, 2)
+	for _, e := range c.expiry {
+		assert.NotEqual(t, 1, e.key)
+	}
+}
+
+func TestBackgroundCleanup(t *testing.T) {
+	expired := make(chan int, 1)
+	c := New(Config[int, string]{
+		Capacity:        10,
+		TTL:             time.Millisecond,
+		CleanupInterval: time.Millisecond,
+		OnEvict: func(key int, _ string, reason EvictionReason) {
+			assert.Equal(t, Expired, reason)
+			expired <- key
+		},
+	})
+	defer c.Close()
+
+	c.Set(1, "one")
+
+	select {
+	case key := <-expired:
+		assert.Equal(t, 1, key)
+	case <-time.After(time.Second):
+		t.Fatal("entry wasn't expired")
+	}
+
+	assert.Zero(t, c.Len())
+	c.Close()
+}
+
+func TestCallbackCanUseCache(t *testing.T) {
+	var c *Cache[int, string]
+	c = New(Config[int, string]{
+		Capacity: 1,
+		OnEvict: func(key int, value string, reason EvictionReason) {
+			if reason == Evicted {
+				c.Get(key)
+			}
+		},
+	})
+
+	c.Set(1, "one")
+	c.Set(2, "two")
+	assert.Equal(t, uint64(1), c.Stats().Misses)
+}
+
+func TestSharded(t *testing.T) {
+	var log evictionLog
+	c := NewSharded(Config[int, string]{
+		Capacity: 64,
+		Shards:   4,
+		OnEvict:  log.onEvict,
+	})
+	defer c.Close()
+
+	require.Len(t, c.shards, 4)
+	assert.Equal(t, 16, c.shards[0].policy.(*lru[int, string]).capacity)
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				key := (worker*1000 + i) % 100
+				if _, found := c.Get(key); !found {
+					c.Set(key, "value")
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, c.Len(), 64)
+	stats := c.Stats()
+	assert.Equal(t, uint64(8000), stats.Hits+stats.Misses)
+
+	log.mutex.Lock()
+	assert.Equal(t, uint64(len(log.keys)), stats.Evictions)
+	log.mutex.Unlock()
+
+	c.SetWithTTL(1000, "ttl", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	c.DeleteExpired()
+	assert.Equal(t, uint64(1), c.Stats().Expirations)
+
+	c.Purge()
+	assert.Zero(t, c.Len())
+}
+
+func TestShardedCapacity(t *testing.T) {
+	shardCapacities := func(c *Sharded[int, int]) []int {
+		capacities := make([]int, len(c.shards))
+		for idx, shard := range c.shards {
+			capacities[idx] = shard.policy.(*lru[int, int]).capacity
+		}
+
+		return capacities
+	}
+
+	c := NewSharded(Config[int, int]{Capacity: 10, Shards: 8})
+	defer c.Close()
+	assert.Equal(t, []int{2, 2, 1, 1, 1, 1, 1, 1}, shardCapacities(c))
+
+	small := NewSharded(Config[int, int]{Capacity: 3, Shards: 8})
+	defer small.Close()
+	assert.Equal(t, []int{1, 1, 1}, shardCapacities(small))
+
+	for i := range 100 {
+		c.Set(i, i)
+		small.Set(i, i)
+	}
+
+	assert.LessOrEqual(t, c.Len(), 10)
+	assert.LessOrEqual(t, small.Len(), 3)
+}
+
+func TestInvalidCapacity(t *testing.T) {
+	assert.Panics(t, func() { New(Config[int, int]{}) })
+	assert.Panics(t, func() { NewSharded(Config[int, int]{Capacity: -1}) })
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"container/heap"
+	"time"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time // zero when the entry doesn't expire
+	heapIdx   int       // -1 when the entry isn't in expiryHeap
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// expiryHeap orders entries with a TTL by expiration time,
+// so expired entries are found without scanning the cache.
+type expiryHeap[K comparable, V any] []*entry[K, V]
+
+func (h expiryHeap[K, V]) Len() int { return len(h) }
+
+func (h expiryHeap[K, V]) Less(i, j int) bool {
+	return h[i].expiresAt.Before(h[j].expiresAt)
+}
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIdx = i
+	h[j].heapIdx = j
+}
+
+func (h *expiryHeap[K, V]) Push(value any) {
+	e := value.(*entry[K, V])
+	e.heapIdx = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	e.heapIdx = -1
+	return e
+}
+
+// update keeps the heap in sync after expiresAt of e changed.
+func (h *expiryHeap[K, V]) update(e *entry[K, V]) {
+	switch {
+	case e.heapIdx >= 0 && e.expiresAt.IsZero():
+		heap.Remove(h, e.heapIdx)
+	case e.heapIdx >= 0:
+		heap.Fix(h, e.heapIdx)
+	case !e.expiresAt.IsZero():
+		heap.Push(h, e)
+	}
+}
+
+func (h *expiryHeap[K, V]) remove(e *entry[K, V]) {
+	if e.heapIdx >= 0 {
+		heap.Remove(h, e.heapIdx)
+	}
+}
+
+func (h expiryHeap[K, V]) peek() *entry[K, V] {
+	if len(h) == 0 {
+		return nil
+	}
+
+	return h[0]
+}
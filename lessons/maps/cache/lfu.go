@@ -0,0 +1,109 @@
+package cache
+
+// lfu keeps buckets of entries with the same number of accesses in
+// a list sorted by frequency, so every operation is O(1). Entries
+// with the same frequency are evicted in LRU order.
+type lfu[K comparable, V any] struct {
+	capacity int
+	items    map[K]*node[*lfuItem[K, V]]
+	buckets  list[*lfuBucket[K, V]]
+}
+
+type lfuBucket[K comparable, V any] struct {
+	frequency int
+	items     list[*lfuItem[K, V]]
+}
+
+type lfuItem[K comparable, V any] struct {
+	entry  *entry[K, V]
+	bucket *node[*lfuBucket[K, V]]
+}
+
+func newLFU[K comparable, V any](capacity int) *lfu[K, V] {
+	return &lfu[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*node[*lfuItem[K, V]], capacity),
+	}
+}
+
+func (c *lfu[K, V]) get(key K) (*entry[K, V], bool) {
+	n, found := c.items[key]
+	if !found {
+		return nil, false
+	}
+
+	item := n.value
+	current := item.bucket
+	next := current.next
+	if next == &c.buckets.root || next.value.frequency != current.value.frequency+1 {
+		next = &node[*lfuBucket[K, V]]{value: &lfuBucket[K, V]{frequency: current.value.frequency + 1}}
+		c.buckets.insertAfter(next, current)
+	}
+
+	current.value.items.remove(n)
+	item.bucket = next
+	next.value.items.pushNodeFront(n)
+
+	if current.value.items.len() == 0 {
+		c.buckets.remove(current)
+	}
+
+	return item.entry, true
+}
+
+func (c *lfu[K, V]) add(e *entry[K, V]) []*entry[K, V] {
+	var evicted []*entry[K, V]
+	if len(c.items) >= c.capacity {
+		evicted = append(evicted, c.evict())
+	}
+
+	first := c.buckets.front()
+	if first == nil || first.value.frequency != 1 {
+		first = c.buckets.pushFront(&lfuBucket[K, V]{frequency: 1})
+	}
+
+	item := &lfuItem[K, V]{entry: e, bucket: first}
+	c.items[e.key] = first.value.items.pushFront(item)
+	return evicted
+}
+
+func (c *lfu[K, V]) evict() *entry[K, V] {
+	bucket := c.buckets.front()
+	victim := bucket.value.items.back()
+
+	bucket.value.items.remove(victim)
+	if bucket.value.items.len() == 0 {
+		c.buckets.remove(bucket)
+	}
+
+	delete(c.items, victim.value.entry.key)
+	return victim.value.entry
+}
+
+func (c *lfu[K, V]) remove(key K) (*entry[K, V], bool) {
+	n, found := c.items[key]
+	if !found {
+		return nil, false
+	}
+
+	bucket := n.value.bucket
+	bucket.value.items.remove(n)
+	if bucket.value.items.len() == 0 {
+		c.buckets.remove(bucket)
+	}
+
+	delete(c.items, key)
+	return n.value.entry, true
+}
+
+func (c *lfu[K, V]) len() int {
+	return len(c.items)
+}
+
+func (c *lfu[K, V]) each(action func(*entry[K, V])) {
+	c.buckets.each(func(bucket *lfuBucket[K, V]) {
+		bucket.items.each(func(item *lfuItem[K, V]) {
+			action(item.entry)
+		})
+	})
+}
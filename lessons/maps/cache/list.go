@@ -0,0 +1,84 @@
+package cache
+
+// list is a generic version of container/list without interface
+// boxing. The front is the most recently used element.
+type node[T any] struct {
+	value T
+	prev  *node[T]
+	next  *node[T]
+}
+
+type list[T any] struct {
+	root   node[T]
+	length int
+}
+
+func (l *list[T]) lazyInit() {
+	if l.root.next == nil {
+		l.root.next = &l.root
+		l.root.prev = &l.root
+	}
+}
+
+func (l *list[T]) len() int {
+	return l.length
+}
+
+func (l *list[T]) pushFront(value T) *node[T] {
+	n := &node[T]{value: value}
+	l.pushNodeFront(n)
+	return n
+}
+
+// pushNodeFront inserts a node removed from another list without allocating.
+func (l *list[T]) pushNodeFront(n *node[T]) {
+	l.lazyInit()
+	l.insertAfter(n, &l.root)
+}
+
+func (l *list[T]) insertAfter(n, at *node[T]) {
+	n.prev = at
+	n.next = at.next
+	at.next.prev = n
+	at.next = n
+	l.length++
+}
+
+func (l *list[T]) remove(n *node[T]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev = nil
+	n.next = nil
+	l.length--
+}
+
+func (l *list[T]) moveToFront(n *node[T]) {
+	if l.root.next == n {
+		return
+	}
+
+	l.remove(n)
+	l.insertAfter(n, &l.root)
+}
+
+func (l *list[T]) front() *node[T] {
+	if l.length == 0 {
+		return nil
+	}
+
+	return l.root.next
+}
+
+func (l *list[T]) back() *node[T] {
+	if l.length == 0 {
+		return nil
+	}
+
+	return l.root.prev
+}
+
+func (l *list[T]) each(action func(T)) {
+	for n := l.front(); n != nil && n != &l.root; n = n.next {
+		action(n.value)
+	}
+}
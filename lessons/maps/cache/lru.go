@@ -0,0 +1,56 @@
+package cache
+
+type lru[K comparable, V any] struct {
+	capacity int
+	items    map[K]*node[*entry[K, V]]
+	order    list[*entry[K, V]]
+}
+
+func newLRU[K comparable, V any](capacity int) *lru[K, V] {
+	return &lru[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*node[*entry[K, V]], capacity),
+	}
+}
+
+func (c *lru[K, V]) get(key K) (*entry[K, V], bool) {
+	n, found := c.items[key]
+	if !found {
+		return nil, false
+	}
+
+	c.order.moveToFront(n)
+	return n.value, true
+}
+
+func (c *lru[K, V]) add(e *entry[K, V]) []*entry[K, V] {
+	var evicted []*entry[K, V]
+	if len(c.items) >= c.capacity {
+		oldest := c.order.back()
+		c.order.remove(oldest)
+		delete(c.items, oldest.value.key)
+		evicted = append(evicted, oldest.value)
+	}
+
+	c.items[e.key] = c.order.pushFront(e)
+	return evicted
+}
+
+func (c *lru[K, V]) remove(key K) (*entry[K, V], bool) {
+	n, found := c.items[key]
+	if !found {
+		return nil, false
+	}
+
+	c.order.remove(n)
+	delete(c.items, key)
+	return n.value, true
+}
+
+func (c *lru[K, V]) len() int {
+	return len(c.items)
+}
+
+func (c *lru[K, V]) each(action func(*entry[K, V])) {
+	c.order.each(action)
+}
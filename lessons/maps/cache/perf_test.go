@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// go test -bench=. -benchmem .
+
+const (
+	benchmarkCapacity = 1_000
+	benchmarkKeys     = 100_000
+)
+
+type store interface {
+	Get(key int) (int, bool)
+	Set(key int, value int)
+	Stats() Stats
+}
+
+// zipfKeys makes some keys much more popular than others, like real traffic.
+func zipfKeys(count int) []int {
+	zipf := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.1, 1, benchmarkKeys-1)
+	keys := make([]int, count)
+	for i := range keys {
+		keys[i] = int(zipf.Uint64())
+	}
+
+	return keys
+}
+
+func getOrSet(c store, key int) {
+	if _, found := c.Get(key); !found {
+		c.Set(key, key)
+	}
+}
+
+func BenchmarkPolicies(b *testing.B) {
+	keys := zipfKeys(1 << 16)
+	for _, policy := range []Policy{LRU, LFU, ARC} {
+		b.Run(policy.String(), func(b *testing.B) {
+			c := New(Config[int, int]{Capacity: benchmarkCapacity, Policy: policy})
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				getOrSet(c, keys[i%len(keys)])
+			}
+
+			b.ReportMetric(c.Stats().HitRatio(), "hit-ratio")
+		})
+	}
+}
+
+func BenchmarkParallel(b *testing.B) {
+	keys := zipfKeys(1 << 16)
+	for _, shards := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			var c store = New(Config[int, int]{Capacity: benchmarkCapacity})
+			if shards > 1 {
+				c = NewSharded(Config[int, int]{Capacity: benchmarkCapacity, Shards: shards})
+			}
+
+			b.RunParallel(func(pb *testing.PB) {
+				i := rand.IntN(len(keys))
+				for pb.Next() {
+					getOrSet(c, keys[i%len(keys)])
+					i++
+				}
+			})
+		})
+	}
+}
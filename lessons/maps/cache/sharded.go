@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"hash/maphash"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Sharded splits keys between independent caches to reduce lock
+// contention. Eviction order is kept per shard, so with a skewed key
+// distribution an entry can be evicted while other shards have space.
+type Sharded[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*Cache[K, V]
+	stop   chan struct{}
+	once   sync.Once
+}
+
+func NewSharded[K comparable, V any](config Config[K, V]) *Sharded[K, V] {
+	shardsNumber := config.Shards
+	if shardsNumber <= 0 {
+		shardsNumber = runtime.GOMAXPROCS(0)
+	}
+
+	if config.Capacity <= 0 {
+		panic("capacity must be positive")
+	}
+
+	// Every shard needs room for at least one entry.
+	shardsNumber = min(shardsNumber, config.Capacity)
+
+	s := &Sharded[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*Cache[K, V], shardsNumber),
+	}
+
+	// The remainder is spread over the first shards, so the capacities
+	// add up to config.Capacity exactly.
+	for i := range s.shards {
+		shardConfig := config
+		shardConfig.Capacity = config.Capacity / shardsNumber
+		if i < config.Capacity%shardsNumber {
+			shardConfig.Capacity++
+		}
+
+		s.shards[i] = newCache(shardConfig)
+	}
+
+	if config.CleanupInterval > 0 {
+		s.stop = make(chan struct{})
+		go runCleanup(config.CleanupInterval, s.stop, s.DeleteExpired)
+	}
+
+	return s
+}
+
+func (s *Sharded[K, V]) shard(key K) *Cache[K, V] {
+	hash := maphash.Comparable(s.seed, key)
+	return s.shards[hash%uint64(len(s.shards))]
+}
+
+func (s *Sharded[K, V]) Get(key K) (V, bool) {
+	return s.shard(key).Get(key)
+}
+
+func (s *Sharded[K, V]) Set(key K, value V) {
+	s.shard(key).Set(key, value)
+}
+
+func (s *Sharded[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	s.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (s *Sharded[K, V]) Delete(key K) bool {
+	return s.shard(key).Delete(key)
+}
+
+func (s *Sharded[K, V]) DeleteExpired() {
+	for _, shard := range s.shards {
+		shard.DeleteExpired()
+	}
+}
+
+func (s *Sharded[K, V]) Purge() {
+	for _, shard := range s.shards {
+		shard.Purge()
+	}
+}
+
+func (s *Sharded[K, V]) Len() int {
+	var length int
+	for _, shard := range s.shards {
+		length += shard.Len()
+	}
+
+	return length
+}
+
+func (s *Sharded[K, V]) Stats() Stats {
+	var stats Stats
+	for _, shard := range s.shards {
+		stats = stats.add(shard.Stats())
+	}
+
+	return stats
+}
+
+func (s *Sharded[K, V]) Close() {
+	s.once.Do(func() {
+		if s.stop != nil {
+			close(s.stop)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package hashmap
+
+import "math/bits"
+
+// Every group has 8 slots and a control word with one byte per slot.
+// A full slot stores the low 7 bits of the key hash (h2), so most
+// mismatches are rejected without comparing keys. All bytes of a
+// control word are checked at once with bit tricks instead of SIMD.
+const (
+	groupSize = 8
+
+	ctrlEmpty   = 0b1000_0000
+	ctrlDeleted = 0b1111_1110
+
+	bitsetLSB = 0x0101010101010101
+	bitsetMSB = 0x8080808080808080
+
+	emptyGroup = ctrlEmpty * bitsetLSB
+)
+
+type ctrlGroup uint64
+
+func (g ctrlGroup) get(i int) uint8 {
+	return uint8(g >> (8 * i))
+}
+
+func (g *ctrlGroup) set(i int, ctrl uint8) {
+	*g = *g&^(0xff<<(8*i)) | ctrlGroup(ctrl)<<(8*i)
+}
+
+// matchH2 can report false positives for bytes next to a real match,
+// it isn't a problem because keys are compared anyway.
+func (g ctrlGroup) matchH2(h2 uint8) bitset {
+	v := uint64(g) ^ (bitsetLSB * uint64(h2))
+	return bitset(((v - bitsetLSB) &^ v) & bitsetMSB)
+}
+
+func (g ctrlGroup) matchEmpty() bitset {
+	v := uint64(g)
+	return bitset((v &^ (v << 6)) & bitsetMSB)
+}
+
+func (g ctrlGroup) matchEmptyOrDeleted() bitset {
+	return bitset(uint64(g) & bitsetMSB)
+}
+
+func (g ctrlGroup) matchFull() bitset {
+	return bitset(^uint64(g) & bitsetMSB)
+}
+
+// bitset has the high bit set in every matched byte.
+type bitset uint64
+
+func (b bitset) first() int {
+	return bits.TrailingZeros64(uint64(b)) >> 3
+}
+
+func (b bitset) removeFirst() bitset {
+	return b & (b - 1)
+}
+
+// probe visits groups at triangular offsets (1, 2, 3...), with a power
+// of two number of groups it visits each of them exactly once.
+type probe struct {
+	mask   uint64
+	offset uint64
+	index  uint64
+}
+
+func newProbe(h1 uint64, mask uint64) probe {
+	return probe{mask: mask, offset: h1 & mask}
+}
+
+func (p *probe) next() {
+	p.index++
+	p.offset = (p.offset + p.index) & p.mask
+}
@@ -0,0 +1,327 @@
+package hashmap
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+)
+
+const defaultLoadFactor = 7.0 / 8.0
+
+type Config[K comparable] struct {
+	Capacity int
+
+	// LoadFactor is the share of slots that can be used by entries and
+	// tombstones before the table is rehashed, 7/8 when zero.
+	LoadFactor float64
+
+	// Hash is maphash.Comparable with a random seed when nil.
+	// Both the low 7 bits and the high bits have to be well mixed.
+	Hash func(K) uint64
+}
+
+type slot[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// HashMap is an open addressing hash table with Swiss table layout.
+// Entries are stored inline, so when K and V don't contain pointers the
+// table is allocated as memory that GC doesn't scan, whatever the value
+// size is. The built-in map stores values larger than 128 bytes as
+// separate objects instead. For string keys see StringMap.
+type HashMap[K comparable, V any] struct {
+	ctrl  []ctrlGroup
+	slots []slot[K, V] // groupSize slots for each control group
+
+	hash       func(K) uint64
+	loadFactor float64
+
+	length     int
+	tombstones int
+	growthLeft int // empty slots that can be used before rehash
+}
+
+func New[K comparable, V any]() *HashMap[K, V] {
+	return NewWithConfig[K, V](Config[K]{})
+}
+
+func NewWithConfig[K comparable, V any](config Config[K]) *HashMap[K, V] {
+	if config.LoadFactor < 0 || config.LoadFactor > 1 {
+		panic(fmt.Sprintf("load factor %v is out of range (0, 1]", config.LoadFactor))
+	}
+
+	m := &HashMap[K, V]{
+		hash:       config.Hash,
+		loadFactor: config.LoadFactor,
+	}
+
+	if m.loadFactor == 0 {
+		m.loadFactor = defaultLoadFactor
+	}
+
+	if m.hash == nil {
+		seed := maphash.MakeSeed()
+		m.hash = func(key K) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+
+	if config.Capacity > 0 {
+		m.resize(m.groupsFor(config.Capacity))
+	}
+
+	return m
+}
+
+func splitHash(hash uint64) (h1 uint64, h2 uint8) {
+	return hash >> 7, uint8(hash & 0x7f)
+}
+
+func (m *HashMap[K, V]) Get(key K) (V, bool) {
+	if idx, found := m.find(key); found {
+		return m.slots[idx].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+func (m *HashMap[K, V]) Contains(key K) bool {
+	_, found := m.find(key)
+	return found
+}
+
+func (m *HashMap[K, V]) find(key K) (int, bool) {
+	if m.length == 0 {
+		return 0, false
+	}
+
+	h1, h2 := splitHash(m.hash(key))
+	for p := newProbe(h1, uint64(len(m.ctrl)-1)); ; p.next() {
+		group := m.ctrl[p.offset]
+		for match := group.matchH2(h2); match != 0; match = match.removeFirst() {
+			idx := int(p.offset)*groupSize + match.first()
+			if m.slots[idx].key == key {
+				return idx, true
+			}
+		}
+
+		if group.matchEmpty() != 0 {
+			return 0, false
+		}
+	}
+}
+
+func (m *HashMap[K, V]) Set(key K, value V) {
+	if m.ctrl == nil {
+		m.resize(1)
+	}
+
+	h1, h2 := splitHash(m.hash(key))
+	insertIdx := -1
+	for p := newProbe(h1, uint64(len(m.ctrl)-1)); ; p.next() {
+		group := m.ctrl[p.offset]
+		for match := group.matchH2(h2); match != 0; match = match.removeFirst() {
+			idx := int(p.offset)*groupSize + match.first()
+			if m.slots[idx].key == key {
+				m.slots[idx].value = value
+				return
+			}
+		}
+
+		if insertIdx < 0 {
+			if match := group.matchEmptyOrDeleted(); match != 0 {
+				insertIdx = int(p.offset)*groupSize + match.first()
+			}
+		}
+
+		if group.matchEmpty() != 0 {
+			break
+		}
+	}
+
+	m.insert(insertIdx, h1, h2, slot[K, V]{key: key, value: value})
+}
+
+// insert puts a new entry to the slot found by probing, the table can
+// be rehashed first, then the slot is looked up again.
+func (m *HashMap[K, V]) insert(insertIdx int, h1 uint64, h2 uint8, entry slot[K, V]) {
+	if m.ctrlAt(insertIdx) == ctrlDeleted {
+		m.tombstones--
+	} else if m.growthLeft == 0 {
+		m.rehash()
+		insertIdx = m.findInsertSlot(h1)
+		m.growthLeft--
+	} else {
+		m.growthLeft--
+	}
+
+	m.ctrl[insertIdx/groupSize].set(insertIdx%groupSize, h2)
+	m.slots[insertIdx] = entry
+	m.length++
+}
+
+// findInsertSlot is used after rehash, when there are no tombstones.
+func (m *HashMap[K, V]) findInsertSlot(h1 uint64) int {
+	for p := newProbe(h1, uint64(len(m.ctrl)-1)); ; p.next() {
+		if match := m.ctrl[p.offset].matchEmptyOrDeleted(); match != 0 {
+			return int(p.offset)*groupSize + match.first()
+		}
+	}
+}
+
+func (m *HashMap[K, V]) ctrlAt(idx int) uint8 {
+	return m.ctrl[idx/groupSize].get(idx % groupSize)
+}
+
+func (m *HashMap[K, V]) Delete(key K) bool {
+	idx, found := m.find(key)
+	if !found {
+		return false
+	}
+
+	m.deleteAt(idx)
+	return true
+}
+
+func (m *HashMap[K, V]) deleteAt(idx int) {
+	// A probe sequence never goes past a group with an empty slot,
+	// so the slot can be emptied instead of leaving a tombstone.
+	group := &m.ctrl[idx/groupSize]
+	if group.matchEmpty() != 0 {
+		group.set(idx%groupSize, ctrlEmpty)
+		m.growthLeft++
+	} else {
+		group.set(idx%groupSize, ctrlDeleted)
+		m.tombstones++
+	}
+
+	m.slots[idx] = slot[K, V]{}
+	m.length--
+}
+
+func (m *HashMap[K, V]) Len() int {
+	return m.length
+}
+
+// Cap returns the number of entries the map can hold without rehash.
+func (m *HashMap[K, V]) Cap() int {
+	return m.threshold(len(m.ctrl))
+}
+
+// Clear removes all entries and keeps the allocated memory.
+func (m *HashMap[K, V]) Clear() {
+	for i := range m.ctrl {
+		m.ctrl[i] = emptyGroup
+	}
+
+	clear(m.slots)
+	m.length = 0
+	m.tombstones = 0
+	m.growthLeft = m.threshold(len(m.ctrl))
+}
+
+// All iterates in hash order. Entries deleted during the iteration
+// aren't produced, entries added during it may or may not be produced.
+func (m *HashMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ctrl, slots := m.ctrl, m.slots
+		for groupIdx := range ctrl {
+			for match := ctrl[groupIdx].matchFull(); match != 0; match = match.removeFirst() {
+				slotIdx := match.first()
+				if ctrl[groupIdx].get(slotIdx)&ctrlEmpty != 0 {
+					continue // deleted during the iteration
+				}
+
+				entry := slots[groupIdx*groupSize+slotIdx]
+				if len(m.ctrl) == 0 || &m.ctrl[0] != &ctrl[0] {
+					// the table was rehashed, so the entry can be stale
+					value, found := m.Get(entry.key)
+					if !found {
+						continue
+					}
+
+					entry.value = value
+				}
+
+				if !yield(entry.key, entry.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (m *HashMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func (m *HashMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// threshold keeps at least one empty slot, so probing always stops.
+func (m *HashMap[K, V]) threshold(groups int) int {
+	slots := groups * groupSize
+	return max(0, min(int(float64(slots)*m.loadFactor), slots-1))
+}
+
+func (m *HashMap[K, V]) groupsFor(entries int) int {
+	groups := 1
+	for m.threshold(groups) < entries {
+		groups *= 2
+	}
+
+	return groups
+}
+
+// rehash grows the table when it is more than half full, otherwise
+// it only cleans tombstones.
+func (m *HashMap[K, V]) rehash() {
+	groups := len(m.ctrl)
+	if m.length+1 > m.threshold(groups)/2 {
+		groups = max(groups*2, m.groupsFor(m.length+1))
+	}
+
+	m.resize(groups)
+}
+
+func (m *HashMap[K, V]) resize(groups int) {
+	if bits.OnesCount(uint(groups)) != 1 {
+		panic("number of groups must be a power of two")
+	}
+
+	oldCtrl, oldSlots := m.ctrl, m.slots
+	m.ctrl = make([]ctrlGroup, groups)
+	m.slots = make([]slot[K, V], groups*groupSize)
+	for i := range m.ctrl {
+		m.ctrl[i] = emptyGroup
+	}
+
+	for groupIdx := range oldCtrl {
+		for match := oldCtrl[groupIdx].matchFull(); match != 0; match = match.removeFirst() {
+			old := &oldSlots[groupIdx*groupSize+match.first()]
+			h1, h2 := splitHash(m.hash(old.key))
+			idx := m.findInsertSlot(h1)
+			m.ctrl[idx/groupSize].set(idx%groupSize, h2)
+			m.slots[idx] = *old
+		}
+	}
+
+	m.tombstones = 0
+	m.growthLeft = m.threshold(groups) - m.length
+}
@@ -0,0 +1,336 @@
+package hashmap
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+func TestControlGroup(t *testing.T) {
+	group := ctrlGroup(emptyGroup)
+	group.set(1, 0x12)
+	group.set(3, ctrlDeleted)
+	group.set(5, 0x12)
+	group.set(6, 0x40)
+
+	positions := func(match bitset) []int {
+		var result []int
+		for ; match != 0; match = match.removeFirst() {
+			result = append(result, match.first())
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []int{1, 5}, positions(group.matchH2(0x12)))
+	assert.Equal(t, []int{0, 2, 4, 7}, positions(group.matchEmpty()))
+	assert.Equal(t, []int{0, 2, 3, 4, 7}, positions(group.matchEmptyOrDeleted()))
+	assert.Equal(t, []int{1, 5, 6}, positions(group.matchFull()))
+	assert.Equal(t, uint8(ctrlDeleted), group.get(3))
+
+	group.set(6, 0x13)
+	assert.Equal(t, []int{1, 5, 6}, positions(group.matchH2(0x12)), "false positive after a match")
+}
+
+func TestProbeVisitsAllGroups(t *testing.T) {
+	for _, groups := range []uint64{1, 2, 8, 64} {
+		visited := make(map[uint64]bool)
+		p := newProbe(12345, groups-1)
+		for range groups {
+			visited[p.offset] = true
+			p.next()
+		}
+
+		assert.Len(t, visited, int(groups))
+	}
+}
+
+func TestHashMap(t *testing.T) {
+	m := New[string, int]()
+	_, found := m.Get("missing")
+	assert.False(t, found)
+	assert.False(t, m.Delete("missing"))
+
+	m.Set("one", 1)
+	m.Set("two", 2)
+	m.Set("one", 10)
+
+	value, found := m.Get("one")
+	assert.True(t, found)
+	assert.Equal(t, 10, value)
+	assert.True(t, m.Contains("two"))
+	assert.Equal(t, 2, m.Len())
+
+	assert.True(t, m.Delete("one"))
+	assert.False(t, m.Contains("one"))
+	assert.Equal(t, 1, m.Len())
+
+	m.Clear()
+	assert.Zero(t, m.Len())
+	assert.False(t, m.Contains("two"))
+}
+
+func TestZeroKey(t *testing.T) {
+	m := New[int, string]()
+	for i := 1; i < 100; i++ {
+		m.Set(i, "value")
+	}
+
+	assert.False(t, m.Contains(0))
+	m.Set(0, "zero")
+	value, _ := m.Get(0)
+	assert.Equal(t, "zero", value)
+}
+
+func TestAgainstBuiltinMap(t *testing.T) {
+	configs := map[string]Config[int]{
+		"default":    {},
+		"dense":      {LoadFactor: 1},
+		"sparse":     {LoadFactor: 0.5, Capacity: 100},
+		"collisions": {Hash: func(key int) uint64 { return uint64(key % 7) }},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			m := NewWithConfig[int, int](config)
+			model := make(map[int]int)
+			random := rand.New(rand.NewPCG(1, 2))
+
+			for i := range 20_000 {
+				key := random.IntN(500)
+				switch random.IntN(3) {
+				case 0:
+					m.Set(key, i)
+					model[key] = i
+				case 1:
+					_, inModel := model[key]
+					assert.Equal(t, inModel, m.Delete(key))
+					delete(model, key)
+				default:
+					value, found := m.Get(key)
+					expected, inModel := model[key]
+					require.Equal(t, inModel, found)
+					require.Equal(t, expected, value)
+				}
+
+				require.Equal(t, len(model), m.Len())
+			}
+
+			assert.Equal(t, model, maps.Collect(m.All()))
+			checkInvariants(t, m)
+		})
+	}
+}
+
+func checkInvariants[K comparable, V any](t *testing.T, m *HashMap[K, V]) {
+	var full, deleted, empty int
+	for _, group := range m.ctrl {
+		for i := range groupSize {
+			switch group.get(i) {
+			case ctrlEmpty:
+				empty++
+			case ctrlDeleted:
+				deleted++
+			default:
+				full++
+			}
+		}
+	}
+
+	assert.Equal(t, m.length, full)
+	assert.Equal(t, m.tombstones, deleted)
+	assert.Equal(t, m.threshold(len(m.ctrl))-full-deleted, m.growthLeft)
+	assert.Positive(t, empty)
+}
+
+func TestTombstonesAreReused(t *testing.T) {
+	m := NewWithConfig[int, int](Config[int]{Capacity: 1000})
+	capacity := m.Cap()
+	groups := len(m.ctrl)
+
+	for i := range 100_000 {
+		m.Set(i, i)
+		if i >= 500 {
+			m.Delete(i - 500)
+		}
+	}
+
+	assert.Equal(t, 500, m.Len())
+	assert.Equal(t, capacity, m.Cap())
+	assert.Equal(t, groups, len(m.ctrl))
+	checkInvariants(t, m)
+}
+
+func TestLoadFactor(t *testing.T) {
+	for _, loadFactor := range []float64{0.25, 0.5, 7.0 / 8.0, 1} {
+		t.Run(fmt.Sprint(loadFactor), func(t *testing.T) {
+			m := NewWithConfig[int, int](Config[int]{LoadFactor: loadFactor})
+			for i := range 10_000 {
+				m.Set(i, i)
+				slots := len(m.ctrl) * groupSize
+				assert.LessOrEqual(t, float64(m.Len()), float64(slots)*loadFactor)
+				assert.Less(t, m.Len(), slots)
+			}
+		})
+	}
+
+	assert.Panics(t, func() { NewWithConfig[int, int](Config[int]{LoadFactor: 1.5}) })
+}
+
+func TestCapacity(t *testing.T) {
+	m := NewWithConfig[int, int](Config[int]{Capacity: 1000})
+	groups := len(m.ctrl)
+	assert.GreaterOrEqual(t, m.Cap(), 1000)
+
+	for i := range 1000 {
+		m.Set(i, i)
+	}
+
+	assert.Equal(t, groups, len(m.ctrl))
+}
+
+func TestIteration(t *testing.T) {
+	m := New[int, int]()
+	for i := range 100 {
+		m.Set(i, i*i)
+	}
+
+	keys := slices.Sorted(m.Keys())
+	assert.Equal(t, 100, len(keys))
+	assert.Equal(t, 0, keys[0])
+	assert.Equal(t, 99, keys[99])
+
+	var sum int
+	for value := range m.Values() {
+		sum += value
+	}
+
+	assert.Equal(t, 328350, sum)
+
+	var count int
+	for range m.All() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	assert.Equal(t, 10, count)
+}
+
+func TestDeleteDuringIteration(t *testing.T) {
+	m := New[int, int]()
+	for i := range 100 {
+		m.Set(i, i)
+	}
+
+	seen := make(map[int]bool)
+	for key := range m.All() {
+		seen[key] = true
+		for other := range 100 {
+			if !seen[other] {
+				m.Delete(other)
+			}
+		}
+	}
+
+	assert.Len(t, seen, 1)
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestUpdateDuringIterationWithRehash(t *testing.T) {
+	m := New[int, int]()
+	for i := range 8 {
+		m.Set(i, i)
+	}
+
+	first := -1
+	for key, value := range m.All() {
+		if first < 0 {
+			first = key
+			for i := range 8 {
+				m.Set(i, -i)
+			}
+
+			m.Delete(7)
+			for i := 100; i < 1000; i++ {
+				m.Set(i, i) // rehash
+			}
+
+			continue
+		}
+
+		assert.NotEqual(t, 7, key)
+		if key < 100 {
+			assert.Equal(t, -key, value)
+		}
+	}
+}
+
+func TestStringMap(t *testing.T) {
+	m := NewStringMap[int](Config[string]{})
+	model := make(map[string]int)
+	random := rand.New(rand.NewPCG(1, 2))
+
+	for i := range 20_000 {
+		key := strings.Repeat("k", random.IntN(3)) + strconv.Itoa(random.IntN(500))
+		switch random.IntN(3) {
+		case 0:
+			m.Set(key, i)
+			model[key] = i
+		case 1:
+			_, inModel := model[key]
+			assert.Equal(t, inModel, m.Delete(key))
+			delete(model, key)
+		default:
+			value, found := m.Get(key)
+			expected, inModel := model[key]
+			require.Equal(t, inModel, found)
+			require.Equal(t, expected, value)
+		}
+
+		require.Equal(t, len(model), m.Len())
+		require.LessOrEqual(t, m.garbage, len(m.arena)/2)
+	}
+
+	assert.Equal(t, model, maps.Collect(m.All()))
+	checkInvariants(t, m.table)
+
+	m.Set("", -1)
+	value, found := m.Get("")
+	assert.True(t, found)
+	assert.Equal(t, -1, value)
+
+	m.Clear()
+	assert.Zero(t, m.Len())
+	assert.Empty(t, m.arena)
+}
+
+func TestStringMapDeleteDuringIteration(t *testing.T) {
+	m := NewStringMap[int](Config[string]{})
+	for i := range 1000 {
+		m.Set(strconv.Itoa(i), i)
+	}
+
+	arena := len(m.arena)
+	for key, value := range m.All() {
+		assert.Equal(t, strconv.Itoa(value), key)
+		m.Delete(key)
+	}
+
+	assert.Zero(t, m.Len())
+	assert.Len(t, m.arena, arena, "compaction must wait for the end of the iteration")
+
+	m.Set("key", 1)
+	assert.True(t, m.Delete("key"))
+	assert.Empty(t, m.arena)
+}
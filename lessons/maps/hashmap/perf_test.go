@@ -0,0 +1,214 @@
+package hashmap
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// go test -bench=. -benchmem .
+
+const benchmarkSize = 1 << 20
+
+var benchmarkSink int
+
+func randomKeys(count int) []int {
+	random := rand.New(rand.NewPCG(1, 2))
+	keys := make([]int, count)
+	for i := range keys {
+		keys[i] = random.Int()
+	}
+
+	return keys
+}
+
+func BenchmarkSet(b *testing.B) {
+	keys := randomKeys(benchmarkSize)
+
+	b.Run("builtin", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := make(map[int]int)
+			for _, key := range keys {
+				m[key] = key
+			}
+		}
+	})
+
+	for _, loadFactor := range []float64{0.5, 7.0 / 8.0, 1} {
+		b.Run(fmt.Sprintf("hashmap/load=%.2f", loadFactor), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				m := NewWithConfig[int, int](Config[int]{LoadFactor: loadFactor})
+				for _, key := range keys {
+					m.Set(key, key)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	keys := randomKeys(benchmarkSize)
+	missing := randomKeys(benchmarkSize + 1)[1:]
+
+	builtin := make(map[int]int, len(keys))
+	hashmap := NewWithConfig[int, int](Config[int]{Capacity: len(keys)})
+	for _, key := range keys {
+		builtin[key] = key
+		hashmap.Set(key, key)
+	}
+
+	b.Run("builtin/hit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchmarkSink += builtin[keys[i%len(keys)]]
+		}
+	})
+
+	b.Run("hashmap/hit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			value, _ := hashmap.Get(keys[i%len(keys)])
+			benchmarkSink += value
+		}
+	})
+
+	b.Run("builtin/miss", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchmarkSink += builtin[missing[i%len(missing)]]
+		}
+	})
+
+	b.Run("hashmap/miss", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			value, _ := hashmap.Get(missing[i%len(missing)])
+			benchmarkSink += value
+		}
+	})
+}
+
+func BenchmarkGetString(b *testing.B) {
+	keys := make([]string, 1<<16)
+	for i := range keys {
+		keys[i] = "key_" + strconv.Itoa(i)
+	}
+
+	builtin := make(map[string]int)
+	hashmap := New[string, int]()
+	pointerFree := NewStringMap[int](Config[string]{})
+	for i, key := range keys {
+		builtin[key] = i
+		hashmap.Set(key, i)
+		pointerFree.Set(key, i)
+	}
+
+	b.Run("builtin", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			benchmarkSink += builtin[keys[i%len(keys)]]
+		}
+	})
+
+	b.Run("hashmap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			value, _ := hashmap.Get(keys[i%len(keys)])
+			benchmarkSink += value
+		}
+	})
+
+	b.Run("pointer-free", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			value, _ := pointerFree.Get(keys[i%len(keys)])
+			benchmarkSink += value
+		}
+	})
+}
+
+// BenchmarkChurn keeps the size fixed, so the table is filled with tombstones.
+func BenchmarkChurn(b *testing.B) {
+	const window = 1 << 16
+
+	b.Run("builtin", func(b *testing.B) {
+		m := make(map[int]int)
+		for i := 0; i < b.N; i++ {
+			m[i] = i
+			delete(m, i-window)
+		}
+	})
+
+	b.Run("hashmap", func(b *testing.B) {
+		m := New[int, int]()
+		for i := 0; i < b.N; i++ {
+			m.Set(i, i)
+			m.Delete(i - window)
+		}
+	})
+}
+
+// BenchmarkGC shows the cost of a GC cycle with a large map alive.
+// Values larger than 128 bytes are allocated separately by the built-in
+// map, and GC has to follow a pointer to each of them, HashMap stores
+// them inline. String keys are pointers in both maps, StringMap keeps
+// them in a byte arena, so its table isn't scanned at all.
+func BenchmarkGC(b *testing.B) {
+	type value [129]byte
+
+	measure := func(b *testing.B, data any) {
+		runtime.GC()
+		b.ResetTimer()
+
+		var total time.Duration
+		for i := 0; i < b.N; i++ {
+			start := time.Now()
+			runtime.GC()
+			total += time.Since(start)
+		}
+
+		b.ReportMetric(float64(total.Microseconds())/float64(b.N), "us/gc")
+		runtime.KeepAlive(data)
+	}
+
+	b.Run("values/builtin", func(b *testing.B) {
+		m := make(map[int]value, benchmarkSize)
+		for i := range benchmarkSize {
+			m[i] = value{}
+		}
+
+		measure(b, m)
+	})
+
+	b.Run("values/hashmap", func(b *testing.B) {
+		m := NewWithConfig[int, value](Config[int]{Capacity: benchmarkSize})
+		for i := range benchmarkSize {
+			m.Set(i, value{})
+		}
+
+		measure(b, m)
+	})
+
+	b.Run("strings/builtin", func(b *testing.B) {
+		m := make(map[string]int, benchmarkSize)
+		for i := range benchmarkSize {
+			m["key_"+strconv.Itoa(i)] = i
+		}
+
+		measure(b, m)
+	})
+
+	b.Run("strings/hashmap", func(b *testing.B) {
+		m := NewWithConfig[string, int](Config[string]{Capacity: benchmarkSize})
+		for i := range benchmarkSize {
+			m.Set("key_"+strconv.Itoa(i), i)
+		}
+
+		measure(b, m)
+	})
+
+	b.Run("strings/pointer-free", func(b *testing.B) {
+		m := NewStringMap[int](Config[string]{Capacity: benchmarkSize})
+		for i := range benchmarkSize {
+			m.Set("key_"+strconv.Itoa(i), i)
+		}
+
+		measure(b, m)
+	})
+}
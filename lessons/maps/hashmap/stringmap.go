@@ -0,0 +1,215 @@
+package hashmap
+
+import (
+	"hash/maphash"
+	"iter"
+	"unsafe"
+)
+
+// stringKey points to the bytes of a key in the arena of StringMap.
+type stringKey struct {
+	offset int
+	length int
+}
+
+// StringMap is the pointer-free mode of HashMap for string keys. Every
+// string is a pointer that GC has to follow, so keys are copied into one
+// byte arena and the table stores their offsets. When V doesn't contain
+// pointers, neither the table nor the arena is scanned by GC, and a map
+// with millions of keys costs a GC cycle as much as an empty one.
+//
+// The bytes of deleted keys stay in the arena until they take more than
+// half of it, then the live keys are compacted. Compaction moves keys,
+// so it is postponed while the map is iterated.
+type StringMap[V any] struct {
+	table     *HashMap[stringKey, V]
+	hash      func(string) uint64
+	arena     []byte
+	garbage   int
+	iterators int
+}
+
+// NewStringMap uses config the same way as NewWithConfig. Hash receives
+// keys that point to the arena, so it must not keep them.
+func NewStringMap[V any](config Config[string]) *StringMap[V] {
+	s := &StringMap[V]{hash: config.Hash}
+	if s.hash == nil {
+		seed := maphash.MakeSeed()
+		s.hash = func(key string) uint64 {
+			return maphash.String(seed, key)
+		}
+	}
+
+	s.table = NewWithConfig[stringKey, V](Config[stringKey]{
+		Capacity:   config.Capacity,
+		LoadFactor: config.LoadFactor,
+		Hash: func(key stringKey) uint64 {
+			return s.hash(s.view(key))
+		},
+	})
+
+	return s
+}
+
+// view returns the key without copying, it is valid until the arena
+// is compacted.
+func (s *StringMap[V]) view(key stringKey) string {
+	if key.length == 0 {
+		return ""
+	}
+
+	return unsafe.String(&s.arena[key.offset], key.length)
+}
+
+func (s *StringMap[V]) find(key string) (int, bool) {
+	m := s.table
+	if m.length == 0 {
+		return 0, false
+	}
+
+	h1, h2 := splitHash(s.hash(key))
+	for p := newProbe(h1, uint64(len(m.ctrl)-1)); ; p.next() {
+		group := m.ctrl[p.offset]
+		for match := group.matchH2(h2); match != 0; match = match.removeFirst() {
+			idx := int(p.offset)*groupSize + match.first()
+			if s.view(m.slots[idx].key) == key {
+				return idx, true
+			}
+		}
+
+		if group.matchEmpty() != 0 {
+			return 0, false
+		}
+	}
+}
+
+func (s *StringMap[V]) Get(key string) (V, bool) {
+	if idx, found := s.find(key); found {
+		return s.table.slots[idx].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+func (s *StringMap[V]) Contains(key string) bool {
+	_, found := s.find(key)
+	return found
+}
+
+func (s *StringMap[V]) Set(key string, value V) {
+	m := s.table
+	if m.ctrl == nil {
+		m.resize(1)
+	}
+
+	h1, h2 := splitHash(s.hash(key))
+	insertIdx := -1
+	for p := newProbe(h1, uint64(len(m.ctrl)-1)); ; p.next() {
+		group := m.ctrl[p.offset]
+		for match := group.matchH2(h2); match != 0; match = match.removeFirst() {
+			idx := int(p.offset)*groupSize + match.first()
+			if s.view(m.slots[idx].key) == key {
+				m.slots[idx].value = value
+				return
+			}
+		}
+
+		if insertIdx < 0 {
+			if match := group.matchEmptyOrDeleted(); match != 0 {
+				insertIdx = int(p.offset)*groupSize + match.first()
+			}
+		}
+
+		if group.matchEmpty() != 0 {
+			break
+		}
+	}
+
+	entry := slot[stringKey, V]{key: stringKey{offset: len(s.arena), length: len(key)}, value: value}
+	s.arena = append(s.arena, key...)
+	m.insert(insertIdx, h1, h2, entry)
+}
+
+func (s *StringMap[V]) Delete(key string) bool {
+	idx, found := s.find(key)
+	if !found {
+		return false
+	}
+
+	s.garbage += s.table.slots[idx].key.length
+	s.table.deleteAt(idx)
+
+	if s.iterators == 0 && s.garbage > len(s.arena)/2 {
+		s.compact()
+	}
+
+	return true
+}
+
+// compact moves the live keys to a new arena, the table isn't rehashed
+// because hashes of the keys don't change.
+func (s *StringMap[V]) compact() {
+	m := s.table
+	arena := make([]byte, 0, len(s.arena)-s.garbage)
+	for groupIdx := range m.ctrl {
+		for match := m.ctrl[groupIdx].matchFull(); match != 0; match = match.removeFirst() {
+			key := &m.slots[groupIdx*groupSize+match.first()].key
+			arena = append(arena, s.arena[key.offset:key.offset+key.length]...)
+			key.offset = len(arena) - key.length
+		}
+	}
+
+	s.arena = arena
+	s.garbage = 0
+}
+
+func (s *StringMap[V]) Len() int {
+	return s.table.Len()
+}
+
+func (s *StringMap[V]) Cap() int {
+	return s.table.Cap()
+}
+
+// Clear removes all entries and keeps the allocated memory.
+func (s *StringMap[V]) Clear() {
+	s.table.Clear()
+	s.arena = s.arena[:0]
+	s.garbage = 0
+}
+
+// All has the same guarantees as HashMap.All, the keys are copied
+// out of the arena.
+func (s *StringMap[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		s.iterators++
+		defer func() { s.iterators-- }()
+
+		for key, value := range s.table.All() {
+			if !yield(string(s.arena[key.offset:key.offset+key.length]), value) {
+				return
+			}
+		}
+	}
+}
+
+func (s *StringMap[V]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for key := range s.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func (s *StringMap[V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range s.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package main
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang_course/lessons/maps/linkedmap"
+)
 
 func main() {
 	data := map[int]struct{}{
@@ -13,4 +18,18 @@ func main() {
 
 	fmt.Println()
 	fmt.Println(data)
+
+	ordered := linkedmap.New[int, struct{}]()
+	for _, key := range []int{5, 1, 4, 2, 3} {
+		ordered.Set(key, struct{}{})
+	}
+
+	for key := range ordered.Keys() {
+		fmt.Print(key, " ") // always in insertion order
+	}
+
+	fmt.Println()
+
+	encoded, _ := json.Marshal(ordered)
+	fmt.Println(string(encoded))
 }
@@ -0,0 +1,149 @@
+package linkedmap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var ErrUnsupportedKey = errors.New("unsupported key type")
+
+// MarshalJSON writes an object with keys in iteration order. Keys are
+// converted like the encoding/json package does for maps: string kinds
+// are used directly, then encoding.TextMarshaler, then integer kinds.
+// The receiver is a pointer, encoding/json doesn't call it for a LinkedMap
+// field of a struct marshaled by value, so such fields must be pointers.
+func (m *LinkedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	first := true
+	for key, value := range m.All() {
+		if !first {
+			buffer.WriteByte(',')
+		}
+
+		first = false
+		text, err := keyToString(key)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedKey, err := json.Marshal(text)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("marshal value of %q: %w", text, err)
+		}
+
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		buffer.Write(encodedValue)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON adds entries in the order of the document,
+// a duplicated key keeps its first position and the last value.
+func (m *LinkedMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected object, got %v", token)
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		key, err := keyFromString[K](token.(string))
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("unmarshal value of %q: %w", token, err)
+		}
+
+		m.Set(key, value)
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+func keyToString[K comparable](key K) (string, error) {
+	value := reflect.ValueOf(&key).Elem()
+	if value.Kind() == reflect.String {
+		return value.String(), nil
+	}
+
+	if marshaler, ok := any(key).(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("%w: %v", ErrUnsupportedKey, value.Type())
+	}
+}
+
+func keyFromString[K comparable](text string) (K, error) {
+	var key K
+	value := reflect.ValueOf(&key).Elem()
+	if value.Kind() == reflect.String {
+		value.SetString(text)
+		return key, nil
+	}
+
+	if unmarshaler, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := unmarshaler.UnmarshalText([]byte(text))
+		return key, err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(text, 10, value.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+
+		value.SetInt(number)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		number, err := strconv.ParseUint(text, 10, value.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+
+		value.SetUint(number)
+	default:
+		return key, fmt.Errorf("%w: %v", ErrUnsupportedKey, value.Type())
+	}
+
+	return key, nil
+}
@@ -0,0 +1,280 @@
+package linkedmap
+
+import "iter"
+
+type element[K comparable, V any] struct {
+	key   K
+	value V
+
+	prev, next *element[K, V]
+	seq        uint64 // grows from the front to the back
+
+	removed bool
+	moved   *element[K, V] // replacement of an element moved during iteration
+}
+
+type config struct {
+	accessOrder bool
+}
+
+type Option func(*config)
+
+// WithAccessOrder moves entries to the back on Get and Set,
+// so the front is the least recently used entry.
+func WithAccessOrder() Option {
+	return func(c *config) {
+		c.accessOrder = true
+	}
+}
+
+// LinkedMap iterates in insertion order. The zero value is an empty
+// map ready to use. Like the built-in map, it isn't safe for concurrent use.
+//
+// The list is linked through a sentinel inside the struct, so a LinkedMap
+// must not be copied after first use: keep it as *LinkedMap, including
+// fields of structs passed to json.Marshal by value.
+type LinkedMap[K comparable, V any] struct {
+	items map[K]*element[K, V]
+	root  element[K, V] // sentinel, root.next is the front
+
+	accessOrder  bool
+	iterators    int
+	placeholders []*element[K, V] // moved during iteration
+	seq          uint64
+}
+
+func New[K comparable, V any](options ...Option) *LinkedMap[K, V] {
+	var c config
+	for _, option := range options {
+		option(&c)
+	}
+
+	m := &LinkedMap[K, V]{accessOrder: c.accessOrder}
+	m.lazyInit()
+	return m
+}
+
+func (m *LinkedMap[K, V]) lazyInit() {
+	if m.items == nil {
+		m.items = make(map[K]*element[K, V])
+		m.root.next = &m.root
+		m.root.prev = &m.root
+	}
+}
+
+func (m *LinkedMap[K, V]) Get(key K) (V, bool) {
+	e, found := m.items[key]
+	if !found {
+		var zero V
+		return zero, false
+	}
+
+	if m.accessOrder {
+		e = m.moveToBack(e)
+	}
+
+	return e.value, true
+}
+
+// Peek doesn't change the order even with WithAccessOrder.
+func (m *LinkedMap[K, V]) Peek(key K) (V, bool) {
+	e, found := m.items[key]
+	if !found {
+		var zero V
+		return zero, false
+	}
+
+	return e.value, true
+}
+
+func (m *LinkedMap[K, V]) Contains(key K) bool {
+	_, found := m.items[key]
+	return found
+}
+
+// Set keeps the position of an existing key unless WithAccessOrder is used.
+func (m *LinkedMap[K, V]) Set(key K, value V) {
+	m.lazyInit()
+	if e, found := m.items[key]; found {
+		if m.accessOrder {
+			e = m.moveToBack(e)
+		}
+
+		e.value = value
+		return
+	}
+
+	e := &element[K, V]{key: key, value: value}
+	m.insertBack(e)
+	m.items[key] = e
+}
+
+func (m *LinkedMap[K, V]) Delete(key K) bool {
+	e, found := m.items[key]
+	if !found {
+		return false
+	}
+
+	delete(m.items, key)
+	m.unlink(e)
+	return true
+}
+
+// MoveToBack works regardless of WithAccessOrder.
+func (m *LinkedMap[K, V]) MoveToBack(key K) bool {
+	e, found := m.items[key]
+	if found {
+		m.moveToBack(e)
+	}
+
+	return found
+}
+
+func (m *LinkedMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+func (m *LinkedMap[K, V]) Front() (K, V, bool) {
+	if m.Len() == 0 {
+		var key K
+		var value V
+		return key, value, false
+	}
+
+	e := m.root.next
+	for e.moved != nil {
+		e = e.next
+	}
+
+	return e.key, e.value, true
+}
+
+func (m *LinkedMap[K, V]) Back() (K, V, bool) {
+	if m.Len() == 0 {
+		var key K
+		var value V
+		return key, value, false
+	}
+
+	e := m.root.prev
+	for e.moved != nil {
+		e = e.prev
+	}
+
+	return e.key, e.value, true
+}
+
+// All iterates from the front to the back in the order the map had when
+// the iteration started, it doesn't move entries even with WithAccessOrder.
+// Entries deleted during the iteration aren't produced if they weren't
+// reached yet, entries added during it aren't produced at all. Entries
+// moved during it are produced at their old position.
+func (m *LinkedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.items == nil {
+			return
+		}
+
+		m.iterators++
+		defer m.finishIteration()
+
+		last := m.seq
+		for e := m.root.next; e != &m.root && e.seq <= last; e = e.next {
+			if e.moved != nil && e.moved.seq <= last {
+				continue // moved before the iteration started
+			}
+
+			current := e
+			for current.moved != nil {
+				current = current.moved
+			}
+
+			if current.removed {
+				continue
+			}
+
+			if !yield(current.key, current.value) {
+				return
+			}
+		}
+	}
+}
+
+func (m *LinkedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func (m *LinkedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+func (m *LinkedMap[K, V]) finishIteration() {
+	m.iterators--
+	if m.iterators > 0 {
+		return
+	}
+
+	for _, e := range m.placeholders {
+		m.detach(e)
+	}
+
+	m.placeholders = nil
+}
+
+func (m *LinkedMap[K, V]) insertBack(e *element[K, V]) {
+	m.seq++
+	e.seq = m.seq
+	e.prev = m.root.prev
+	e.next = &m.root
+	e.prev.next = e
+	m.root.prev = e
+}
+
+func (m *LinkedMap[K, V]) unlink(e *element[K, V]) {
+	m.detach(e)
+	e.removed = true
+
+	var zero V
+	e.value = zero
+}
+
+// detach keeps e.next, so an iterator standing on a detached
+// element can still continue from it.
+func (m *LinkedMap[K, V]) detach(e *element[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+}
+
+// moveToBack relinks e in place, but during an iteration e stays as
+// a placeholder for a new element at the back, so iterators neither
+// jump to the back nor lose the position of e.
+func (m *LinkedMap[K, V]) moveToBack(e *element[K, V]) *element[K, V] {
+	if m.iterators == 0 {
+		m.detach(e)
+		m.insertBack(e)
+		return e
+	}
+
+	moved := &element[K, V]{key: e.key, value: e.value}
+	m.insertBack(moved)
+	m.items[moved.key] = moved
+
+	e.moved = moved
+	var zero V
+	e.value = zero
+	m.placeholders = append(m.placeholders, e)
+	return moved
+}
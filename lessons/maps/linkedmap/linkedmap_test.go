@@ -0,0 +1,381 @@
+package linkedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+func entries[K comparable, V any](m *LinkedMap[K, V]) []string {
+	var result []string
+	for key, value := range m.All() {
+		result = append(result, fmt.Sprintf("%v=%v", key, value))
+	}
+
+	return result
+}
+
+func TestInsertionOrder(t *testing.T) {
+	m := New[string, int]()
+	for i, key := range []string{"foo", "bar", "baz", "qux"} {
+		m.Set(key, i)
+	}
+
+	m.Set("bar", 10) // keeps the position
+	value, found := m.Get("foo")
+	assert.True(t, found)
+	assert.Equal(t, 0, value)
+
+	assert.Equal(t, []string{"foo=0", "bar=10", "baz=2", "qux=3"}, entries(m))
+	assert.Equal(t, []string{"foo", "bar", "baz", "qux"}, slices.Collect(m.Keys()))
+	assert.Equal(t, []int{0, 10, 2, 3}, slices.Collect(m.Values()))
+
+	assert.True(t, m.Delete("bar"))
+	assert.False(t, m.Delete("bar"))
+	m.Set("bar", 20)
+	assert.Equal(t, []string{"foo=0", "baz=2", "qux=3", "bar=20"}, entries(m))
+	assert.Equal(t, 4, m.Len())
+
+	key, value, found := m.Front()
+	assert.True(t, found)
+	assert.Equal(t, "foo", key)
+	assert.Equal(t, 0, value)
+
+	key, _, _ = m.Back()
+	assert.Equal(t, "bar", key)
+
+	assert.True(t, m.MoveToBack("foo"))
+	assert.False(t, m.MoveToBack("missing"))
+	assert.Equal(t, []string{"baz", "qux", "bar", "foo"}, slices.Collect(m.Keys()))
+}
+
+func TestZeroValue(t *testing.T) {
+	var m LinkedMap[int, int]
+	_, found := m.Get(1)
+	assert.False(t, found)
+	_, _, found = m.Front()
+	assert.False(t, found)
+	assert.Empty(t, entries(&m))
+
+	m.Set(2, 2)
+	m.Set(1, 1)
+	assert.Equal(t, []int{2, 1}, slices.Collect(m.Keys()))
+}
+
+func TestAccessOrder(t *testing.T) {
+	m := New[int, string](WithAccessOrder())
+	for i := range 5 {
+		m.Set(i, fmt.Sprint(i))
+	}
+
+	m.Get(1)
+	m.Set(3, "three")
+	m.Peek(0)
+	m.Get(4)
+
+	assert.Equal(t, []int{0, 2, 1, 3, 4}, slices.Collect(m.Keys()))
+
+	key, _, _ := m.Front()
+	assert.Equal(t, 0, key) // the least recently used
+}
+
+func TestDeleteDuringIteration(t *testing.T) {
+	m := New[string, int]()
+	m.Set("foo", 0)
+	m.Set("bar", 1)
+	m.Set("baz", 2)
+
+	var visited []string
+	for key := range m.All() {
+		visited = append(visited, key)
+		if key == "foo" {
+			m.Delete("bar")
+		}
+		if key == "bar" {
+			m.Delete("foo")
+		}
+	}
+
+	assert.Equal(t, []string{"foo", "baz"}, visited)
+	assert.Equal(t, []string{"foo=0", "baz=2"}, entries(m))
+}
+
+func TestDeleteAllDuringIteration(t *testing.T) {
+	m := New[int, int]()
+	for i := range 10 {
+		m.Set(i, i)
+	}
+
+	var visited []int
+	for key := range m.All() {
+		visited = append(visited, key)
+		m.Delete(key)
+		m.Delete(key + 1)
+		m.Delete(key + 2)
+	}
+
+	assert.Equal(t, []int{0, 3, 6, 9}, visited)
+	assert.Zero(t, m.Len())
+}
+
+func TestMoveDuringIteration(t *testing.T) {
+	m := New[int, int](WithAccessOrder())
+	for i := range 5 {
+		m.Set(i, i)
+	}
+
+	var visited []int
+	for key := range m.All() {
+		visited = append(visited, key)
+		if key == 1 {
+			m.Get(1) // the current entry
+			m.Get(3)
+			m.Set(3, 30)
+			m.Set(5, 5)
+			m.Get(4)
+			m.Delete(4)
+
+			key, _, _ := m.Back()
+			assert.Equal(t, 5, key)
+		}
+		if key == 0 {
+			m.Get(0)
+			key, _, _ := m.Front()
+			assert.Equal(t, 1, key)
+		}
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 3}, visited)
+	assert.Equal(t, []string{"2=2", "0=0", "1=1", "3=30", "5=5"}, entries(m))
+	assert.Empty(t, m.placeholders)
+	assert.Zero(t, m.iterators)
+
+	for range m.All() {
+		break
+	}
+
+	assert.Zero(t, m.iterators)
+	m.Get(2)
+	assert.Equal(t, []int{0, 1, 3, 5, 2}, slices.Collect(m.Keys()))
+}
+
+func TestMarshalJSON(t *testing.T) {
+	m := New[string, any]()
+	m.Set("zeta", 1)
+	m.Set("alpha", []int{1, 2})
+	m.Set("mid", map[string]bool{"b": true, "a": false})
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, `{"zeta":1,"alpha":[1,2],"mid":{"a":false,"b":true}}`, string(data))
+
+	empty, err := json.Marshal(New[int, int]())
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(empty))
+}
+
+func TestMarshalJSONInStruct(t *testing.T) {
+	type config struct {
+		Servers *LinkedMap[string, int]
+	}
+
+	c := config{Servers: New[string, int]()}
+	c.Servers.Set("b", 1)
+	c.Servers.Set("a", 2)
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.Equal(t, `{"Servers":{"b":1,"a":2}}`, string(data))
+
+	type valueConfig struct {
+		Servers LinkedMap[string, int]
+	}
+
+	var v valueConfig
+	v.Servers.Set("b", 1)
+
+	data, err = json.Marshal(&v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"Servers":{"b":1}}`, string(data))
+
+	// The field isn't addressable, so the entries are lost, that is
+	// why the documentation asks for a pointer.
+	data, err = json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"Servers":{}}`, string(data))
+}
+
+type point struct {
+	X, Y int
+}
+
+func (p point) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "%d:%d", p.X, p.Y), nil
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d:%d", &p.X, &p.Y)
+	return err
+}
+
+func TestJSONKeys(t *testing.T) {
+	ints := New[int8, string]()
+	ints.Set(3, "three")
+	ints.Set(-1, "minus one")
+
+	data, err := json.Marshal(ints)
+	require.NoError(t, err)
+	assert.Equal(t, `{"3":"three","-1":"minus one"}`, string(data))
+
+	points := New[point, int]()
+	points.Set(point{2, 3}, 1)
+	points.Set(point{0, 0}, 2)
+
+	data, err = json.Marshal(points)
+	require.NoError(t, err)
+	assert.Equal(t, `{"2:3":1,"0:0":2}`, string(data))
+
+	restored := New[point, int]()
+	require.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, []point{{2, 3}, {0, 0}}, slices.Collect(restored.Keys()))
+
+	_, err = json.Marshal(New[float64, int]())
+	assert.NoError(t, err) // there are no keys to convert
+
+	floats := New[float64, int]()
+	floats.Set(1.5, 1)
+	_, err = json.Marshal(floats)
+	assert.ErrorIs(t, err, ErrUnsupportedKey)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"300":"overflow"}`), New[int8, string]()))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	const document = `{"zeta": 1, "alpha": 2, "mid": 3, "alpha": 4}`
+
+	var m LinkedMap[string, int]
+	require.NoError(t, json.Unmarshal([]byte(document), &m))
+	assert.Equal(t, []string{"zeta=1", "alpha=4", "mid=3"}, entries(&m))
+
+	data, err := json.Marshal(&m)
+	require.NoError(t, err)
+	assert.Equal(t, `{"zeta":1,"alpha":4,"mid":3}`, string(data))
+
+	type config struct {
+		Name    string
+		Servers *LinkedMap[string, []string]
+	}
+
+	var c config
+	require.NoError(t, json.Unmarshal([]byte(`{"Name":"test","Servers":{"b":["x"],"a":["y","z"]}}`), &c))
+	assert.Equal(t, []string{"b", "a"}, slices.Collect(c.Servers.Keys()))
+
+	assert.Error(t, json.Unmarshal([]byte(`[1, 2]`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`{"key": "value"}`), &m))
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &m))
+}
+
+func TestDeterministicOutput(t *testing.T) {
+	build := func() string {
+		builtin := make(map[int]int)
+		m := New[int, int]()
+		for i := range 100 {
+			builtin[i] = i
+			m.Set(i, i)
+		}
+
+		for key := range maps.Keys(builtin) {
+			if key%3 == 0 {
+				m.Delete(key)
+			}
+		}
+
+		return strings.Join(entries(m), ",")
+	}
+
+	expected := build()
+	for range 10 {
+		assert.Equal(t, expected, build())
+	}
+}
+
+func TestNestedIteration(t *testing.T) {
+	m := New[int, int](WithAccessOrder())
+	for i := range 3 {
+		m.Set(i, i)
+	}
+
+	var pairs []string
+	for outer := range m.Keys() {
+		for inner := range m.Keys() {
+			pairs = append(pairs, fmt.Sprint(outer, inner))
+			m.Get(inner)
+		}
+
+		assert.NotEmpty(t, m.placeholders)
+	}
+
+	assert.Equal(t, []string{"0 0", "0 1", "0 2", "1 0", "1 1", "1 2", "2 0", "2 1", "2 2"}, pairs)
+	assert.Empty(t, m.placeholders)
+	assert.Equal(t, []int{0, 1, 2}, slices.Collect(m.Keys()))
+}
+
+func TestAgainstModel(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+	m := New[int, int](WithAccessOrder())
+	var model []int
+
+	moveToBack := func(key int) {
+		model = slices.DeleteFunc(model, func(k int) bool { return k == key })
+		model = append(model, key)
+	}
+
+	mutate := func() {
+		key := random.IntN(20)
+		switch random.IntN(3) {
+		case 0:
+			m.Set(key, key)
+			moveToBack(key)
+		case 1:
+			if _, found := m.Get(key); found {
+				moveToBack(key)
+			}
+		default:
+			m.Delete(key)
+			model = slices.DeleteFunc(model, func(k int) bool { return k == key })
+		}
+	}
+
+	for range 200 {
+		expected := slices.Clone(model)
+		var visited []int
+		for key := range m.Keys() {
+			visited = append(visited, key)
+			for range random.IntN(3) {
+				mutate()
+			}
+		}
+
+		for _, key := range visited {
+			assert.Contains(t, expected, key)
+		}
+
+		assert.True(t, slices.IsSortedFunc(visited, func(a, b int) int {
+			return slices.Index(expected, a) - slices.Index(expected, b)
+		}))
+
+		mutate()
+		keys := slices.Collect(m.Keys())
+		require.True(t, slices.Equal(model, keys), "expected %v, got %v", model, keys)
+		require.Equal(t, len(model), m.Len())
+	}
+}
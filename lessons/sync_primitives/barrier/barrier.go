@@ -0,0 +1,85 @@
+package barrier
+
+import (
+	"context"
+	"sync"
+)
+
+// Barrier is a cyclic barrier: every n calls of Await release all
+// waiting goroutines and start a new generation.
+type Barrier struct {
+	parties    int
+	arrived    int
+	generation int
+	action     func()
+	condition  *sync.Cond
+}
+
+func NewBarrier(parties int) *Barrier {
+	return NewBarrierWithAction(parties, nil)
+}
+
+// NewBarrierWithAction creates a barrier that runs action in the last
+// arriving goroutine before the waiting ones are released.
+func NewBarrierWithAction(parties int, action func()) *Barrier {
+	if parties <= 0 {
+		panic("barrier: parties must be positive")
+	}
+
+	return &Barrier{
+		parties:   parties,
+		action:    action,
+		condition: sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// Await blocks until all parties have arrived and returns the generation
+// that was completed. When ctx is finished first, the caller withdraws its
+// arrival, so the other parties keep waiting for a replacement.
+func (b *Barrier) Await(ctx context.Context) (int, error) {
+	b.condition.L.Lock()
+	defer b.condition.L.Unlock()
+
+	generation := b.generation
+	b.arrived++
+
+	if b.arrived == b.parties {
+		if b.action != nil {
+			b.action()
+		}
+
+		b.arrived = 0
+		b.generation++
+		b.condition.Broadcast()
+		return generation, nil
+	}
+
+	err := waitCond(ctx, b.condition, func() bool {
+		return b.generation != generation
+	})
+
+	if err != nil {
+		b.arrived--
+		return generation, err
+	}
+
+	return generation, nil
+}
+
+func (b *Barrier) Parties() int {
+	return b.parties
+}
+
+func (b *Barrier) Waiting() int {
+	b.condition.L.Lock()
+	defer b.condition.L.Unlock()
+
+	return b.arrived
+}
+
+func (b *Barrier) Generation() int {
+	b.condition.L.Lock()
+	defer b.condition.L.Unlock()
+
+	return b.generation
+}
@@ -0,0 +1,176 @@
+package barrier
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+func TestBarrierManyGenerations(t *testing.T) {
+	const partiesNumber = 8
+	const generationsNumber = 500
+
+	var actions atomic.Int32
+	var inPhase atomic.Int32
+	barrier := NewBarrierWithAction(partiesNumber, func() {
+		assert.Equal(t, int32(partiesNumber), inPhase.Swap(0))
+		actions.Add(1)
+	})
+
+	wg := sync.WaitGroup{}
+	wg.Add(partiesNumber)
+	for i := 0; i < partiesNumber; i++ {
+		go func() {
+			defer wg.Done()
+			for generation := 0; generation < generationsNumber; generation++ {
+				inPhase.Add(1)
+				completed, err := barrier.Await(context.Background())
+				assert.NoError(t, err)
+				assert.Equal(t, generation, completed)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(generationsNumber), actions.Load())
+	assert.Equal(t, generationsNumber, barrier.Generation())
+	assert.Zero(t, barrier.Waiting())
+}
+
+func TestBarrierAwaitCancellation(t *testing.T) {
+	barrier := NewBarrier(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := barrier.Await(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, barrier.Waiting())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := barrier.Await(context.Background())
+		assert.NoError(t, err)
+	}()
+
+	_, err = barrier.Await(context.Background())
+	assert.NoError(t, err)
+	<-done
+}
+
+func TestCountDownLatch(t *testing.T) {
+	latch := NewCountDownLatch(3)
+
+	var released atomic.Int32
+	wg := sync.WaitGroup{}
+	wg.Add(5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, latch.Wait(context.Background()))
+			released.Add(1)
+		}()
+	}
+
+	latch.CountDown()
+	latch.CountDown()
+	time.Sleep(10 * time.Millisecond)
+	assert.Zero(t, released.Load())
+	assert.Equal(t, 1, latch.Count())
+
+	latch.CountDown()
+	latch.CountDown()
+	wg.Wait()
+
+	assert.Equal(t, int32(5), released.Load())
+	assert.Zero(t, latch.Count())
+	assert.NoError(t, latch.Wait(context.Background()))
+}
+
+func TestCountDownLatchCancellation(t *testing.T) {
+	latch := NewCountDownLatch(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, latch.Wait(ctx), context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, latch.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestPhaserDynamicParties(t *testing.T) {
+	phaser := NewPhaser(1)
+
+	const workersNumber = 4
+	const phasesNumber = 50
+
+	wg := sync.WaitGroup{}
+	wg.Add(workersNumber)
+	for i := 0; i < workersNumber; i++ {
+		phaser.Register()
+		go func(phases int) {
+			defer wg.Done()
+			for phase := 0; phase < phases; phase++ {
+				_, err := phaser.ArriveAndAwait(context.Background())
+				assert.NoError(t, err)
+			}
+
+			phaser.ArriveAndDeregister()
+		}(phasesNumber + i)
+	}
+
+	phaser.ArriveAndDeregister()
+	wg.Wait()
+
+	assert.Zero(t, phaser.Parties())
+	assert.Equal(t, phasesNumber+workersNumber-1, phaser.Phase())
+}
+
+func TestPhaserArriveAndAwaitAdvance(t *testing.T) {
+	phaser := NewPhaser(2)
+
+	phase := phaser.Arrive()
+	assert.Equal(t, 0, phase)
+	assert.Equal(t, 1, phaser.Arrived())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := phaser.AwaitAdvance(ctx, phase)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	go phaser.Arrive()
+
+	next, err := phaser.AwaitAdvance(context.Background(), phase)
+	require.NoError(t, err)
+	assert.Equal(t, 1, next)
+	assert.Zero(t, phaser.Arrived())
+}
+
+func TestPhaserArriveAndAwaitCancellation(t *testing.T) {
+	phaser := NewPhaser(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	phase, err := phaser.ArriveAndAwait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, phase)
+	assert.Zero(t, phaser.Arrived())
+
+	phaser.Deregister()
+	assert.Equal(t, 1, phaser.Parties())
+
+	phase, err = phaser.ArriveAndAwait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, phase)
+}
@@ -0,0 +1,54 @@
+package barrier
+
+import (
+	"context"
+	"sync"
+)
+
+// CountDownLatch releases all waiters once the counter reaches zero.
+// Unlike Barrier it can't be reused.
+type CountDownLatch struct {
+	count     int
+	condition *sync.Cond
+}
+
+func NewCountDownLatch(count int) *CountDownLatch {
+	if count < 0 {
+		panic("barrier: negative latch count")
+	}
+
+	return &CountDownLatch{
+		count:     count,
+		condition: sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+func (l *CountDownLatch) CountDown() {
+	l.condition.L.Lock()
+	defer l.condition.L.Unlock()
+
+	if l.count == 0 {
+		return
+	}
+
+	l.count--
+	if l.count == 0 {
+		l.condition.Broadcast()
+	}
+}
+
+func (l *CountDownLatch) Count() int {
+	l.condition.L.Lock()
+	defer l.condition.L.Unlock()
+
+	return l.count
+}
+
+func (l *CountDownLatch) Wait(ctx context.Context) error {
+	l.condition.L.Lock()
+	defer l.condition.L.Unlock()
+
+	return waitCond(ctx, l.condition, func() bool {
+		return l.count == 0
+	})
+}
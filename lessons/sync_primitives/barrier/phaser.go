@@ -0,0 +1,149 @@
+package barrier
+
+import (
+	"context"
+	"sync"
+)
+
+// Phaser is a reusable barrier with a dynamic number of parties.
+// The phase advances when every registered party has arrived.
+type Phaser struct {
+	parties   int
+	arrived   int
+	phase     int
+	condition *sync.Cond
+}
+
+func NewPhaser(parties int) *Phaser {
+	if parties < 0 {
+		panic("barrier: negative parties number")
+	}
+
+	return &Phaser{
+		parties:   parties,
+		condition: sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// Register adds a new party and returns the current phase.
+func (p *Phaser) Register() int {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	p.parties++
+	return p.phase
+}
+
+// Deregister removes a party that hasn't arrived in the current phase.
+func (p *Phaser) Deregister() int {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	p.removeParty()
+	return p.phase
+}
+
+// Arrive marks the party as arrived without waiting for the others
+// and returns the phase it arrived at.
+func (p *Phaser) Arrive() int {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	return p.arrive()
+}
+
+// ArriveAndDeregister marks the party as arrived and removes it
+// from the next phases.
+func (p *Phaser) ArriveAndDeregister() int {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	phase := p.phase
+	p.removeParty()
+	return phase
+}
+
+// ArriveAndAwait arrives and waits for the other parties. It returns
+// the next phase number.
+func (p *Phaser) ArriveAndAwait(ctx context.Context) (int, error) {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	phase := p.arrive()
+	err := waitCond(ctx, p.condition, func() bool {
+		return p.phase != phase
+	})
+
+	if err != nil {
+		if p.phase == phase {
+			p.arrived--
+		}
+
+		return p.phase, err
+	}
+
+	return p.phase, nil
+}
+
+// AwaitAdvance waits until the phaser moves past phase.
+func (p *Phaser) AwaitAdvance(ctx context.Context, phase int) (int, error) {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	err := waitCond(ctx, p.condition, func() bool {
+		return p.phase != phase
+	})
+
+	return p.phase, err
+}
+
+func (p *Phaser) Phase() int {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	return p.phase
+}
+
+func (p *Phaser) Parties() int {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	return p.parties
+}
+
+func (p *Phaser) Arrived() int {
+	p.condition.L.Lock()
+	defer p.condition.L.Unlock()
+
+	return p.arrived
+}
+
+func (p *Phaser) arrive() int {
+	if p.arrived >= p.parties {
+		panic("barrier: arrivals exceed registered parties")
+	}
+
+	phase := p.phase
+	p.arrived++
+	p.tryAdvance()
+	return phase
+}
+
+func (p *Phaser) removeParty() {
+	if p.parties == 0 {
+		panic("barrier: no registered parties")
+	}
+
+	p.parties--
+	p.tryAdvance()
+}
+
+func (p *Phaser) tryAdvance() {
+	if p.arrived == 0 || p.arrived < p.parties {
+		return
+	}
+
+	p.arrived = 0
+	p.phase++
+	p.condition.Broadcast()
+}
@@ -0,0 +1,37 @@
+package barrier
+
+import (
+	"context"
+	"sync"
+)
+
+// waitCond waits on cond until done returns true or ctx is finished.
+// cond.L must be held by the caller. sync.Cond knows nothing about
+// contexts, so a context.AfterFunc wakes up all waiters on cancellation
+// and each of them re-checks its own context.
+func waitCond(ctx context.Context, cond *sync.Cond, done func() bool) error {
+	if done() {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		cond.L.Lock()
+		defer cond.L.Unlock()
+		cond.Broadcast()
+	})
+	defer stop()
+
+	for !done() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		cond.Wait()
+	}
+
+	return nil
+}
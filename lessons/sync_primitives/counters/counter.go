@@ -0,0 +1,76 @@
+package counters
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+const cacheLineSize = 64
+
+type paddedInt64 struct {
+	value atomic.Int64
+	_     [cacheLineSize - 8]byte
+}
+
+// ShardedCounter spreads increments over padded shards, so
+// goroutines on different cores don't fight for one cache line.
+type ShardedCounter struct {
+	shards []paddedInt64
+	mask   uint32
+}
+
+func NewShardedCounter() *ShardedCounter {
+	return NewShardedCounterWithShards(runtime.GOMAXPROCS(0))
+}
+
+func NewShardedCounterWithShards(count int) *ShardedCounter {
+	size := nextPowerOfTwo(count)
+	return &ShardedCounter{
+		shards: make([]paddedInt64, size),
+		mask:   uint32(size - 1),
+	}
+}
+
+func (c *ShardedCounter) Increment() {
+	c.Add(1)
+}
+
+func (c *ShardedCounter) Add(delta int64) {
+	c.shards[rand.Uint32()&c.mask].value.Add(delta)
+}
+
+func (c *ShardedCounter) AddAt(idx int, delta int64) {
+	c.shards[uint32(idx)&c.mask].value.Add(delta)
+}
+
+func (c *ShardedCounter) Load() int64 {
+	var value int64
+	for idx := range c.shards {
+		value += c.shards[idx].value.Load()
+	}
+
+	return value
+}
+
+func (c *ShardedCounter) Reset() int64 {
+	var value int64
+	for idx := range c.shards {
+		value += c.shards[idx].value.Swap(0)
+	}
+
+	return value
+}
+
+func (c *ShardedCounter) Shards() int {
+	return len(c.shards)
+}
+
+func nextPowerOfTwo(value int) int {
+	size := 1
+	for size < value {
+		size <<= 1
+	}
+
+	return size
+}
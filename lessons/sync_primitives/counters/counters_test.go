@@ -0,0 +1,118 @@
+package counters
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v -race .
+
+func TestShardedCounter(t *testing.T) {
+	counter := NewShardedCounterWithShards(5)
+	assert.Equal(t, 8, counter.Shards())
+
+	const goroutinesNumber = 16
+	const incrementsNumber = 1000
+
+	wg := sync.WaitGroup{}
+	wg.Add(goroutinesNumber)
+	for i := 0; i < goroutinesNumber; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < incrementsNumber; j++ {
+				counter.Increment()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int64(goroutinesNumber*incrementsNumber), counter.Load())
+
+	counter.AddAt(100, -10)
+	assert.Equal(t, int64(goroutinesNumber*incrementsNumber-10), counter.Reset())
+	assert.Zero(t, counter.Load())
+}
+
+func TestShardedCounterSizedByGOMAXPROCS(t *testing.T) {
+	counter := NewShardedCounter()
+	assert.GreaterOrEqual(t, counter.Shards(), 1)
+	assert.Zero(t, counter.Shards()&(counter.Shards()-1))
+}
+
+func TestGauge(t *testing.T) {
+	gauge := NewGauge()
+	gauge.Set(10)
+	gauge.Add(5)
+	gauge.Add(-20)
+
+	assert.Equal(t, GaugeSnapshot{Value: -5, Min: -5, Max: 15}, gauge.Snapshot())
+	assert.Equal(t, GaugeSnapshot{Value: -5, Min: -5, Max: 15}, gauge.Reset())
+	assert.Equal(t, GaugeSnapshot{Value: -5, Min: -5, Max: -5}, gauge.Snapshot())
+
+	gauge.Set(3)
+	assert.Equal(t, GaugeSnapshot{Value: 3, Min: -5, Max: 3}, gauge.Snapshot())
+}
+
+func TestGaugeConcurrent(t *testing.T) {
+	gauge := NewGauge()
+
+	wg := sync.WaitGroup{}
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		go func(value int64) {
+			defer wg.Done()
+			gauge.Set(value)
+		}(int64(i))
+	}
+
+	wg.Wait()
+
+	snapshot := gauge.Snapshot()
+	assert.Equal(t, int64(0), snapshot.Min)
+	assert.Equal(t, int64(9), snapshot.Max)
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	for _, value := range []uint64{0, 1, 7, 8, 9, 15, 16, 17, 1000, 123456789, math.MaxInt64} {
+		idx := bucketIndex(value)
+		lower, upper := bucketBounds(idx)
+		assert.LessOrEqual(t, uint64(lower), value)
+		assert.GreaterOrEqual(t, uint64(upper), value)
+	}
+
+	assert.Equal(t, bucketsCount-1, bucketIndex(math.MaxUint64))
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram()
+	for value := int64(1); value <= 1000; value++ {
+		histogram.Record(value)
+	}
+
+	histogram.Record(-1)
+
+	snapshot := histogram.Snapshot()
+	assert.Equal(t, uint64(1001), snapshot.Count)
+	assert.Equal(t, int64(500500), snapshot.Sum)
+	assert.InDelta(t, 500, snapshot.Mean(), 1)
+	assert.Equal(t, int64(0), snapshot.Min())
+	assert.InEpsilon(t, 1000, snapshot.Max(), 0.125)
+	assert.InEpsilon(t, 500, snapshot.Quantile(0.5), 0.125)
+	assert.InEpsilon(t, 990, snapshot.Quantile(0.99), 0.125)
+
+	var total uint64
+	snapshot.ForEachBucket(func(lower, upper int64, count uint64) {
+		assert.LessOrEqual(t, lower, upper)
+		total += count
+	})
+
+	assert.Equal(t, snapshot.Count, total)
+
+	histogram.Reset()
+	assert.Zero(t, histogram.Snapshot().Count)
+	assert.Zero(t, histogram.Snapshot().Quantile(0.5))
+}
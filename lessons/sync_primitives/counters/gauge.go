@@ -0,0 +1,77 @@
+package counters
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Gauge keeps the current value together with the lowest and
+// highest values seen since the last Reset.
+type Gauge struct {
+	value atomic.Int64
+	min   atomic.Int64
+	max   atomic.Int64
+}
+
+type GaugeSnapshot struct {
+	Value int64
+	Min   int64
+	Max   int64
+}
+
+func NewGauge() *Gauge {
+	gauge := &Gauge{}
+	gauge.min.Store(math.MaxInt64)
+	gauge.max.Store(math.MinInt64)
+	return gauge
+}
+
+func (g *Gauge) Set(value int64) {
+	g.value.Store(value)
+	g.observe(value)
+}
+
+func (g *Gauge) Add(delta int64) int64 {
+	value := g.value.Add(delta)
+	g.observe(value)
+	return value
+}
+
+func (g *Gauge) Load() int64 {
+	return g.value.Load()
+}
+
+func (g *Gauge) Snapshot() GaugeSnapshot {
+	return GaugeSnapshot{
+		Value: g.value.Load(),
+		Min:   g.min.Load(),
+		Max:   g.max.Load(),
+	}
+}
+
+// Reset returns the current snapshot and starts a new min/max
+// window from the current value.
+func (g *Gauge) Reset() GaugeSnapshot {
+	value := g.value.Load()
+	return GaugeSnapshot{
+		Value: value,
+		Min:   g.min.Swap(value),
+		Max:   g.max.Swap(value),
+	}
+}
+
+func (g *Gauge) observe(value int64) {
+	for {
+		current := g.min.Load()
+		if value >= current || g.min.CompareAndSwap(current, value) {
+			break
+		}
+	}
+
+	for {
+		current := g.max.Load()
+		if value <= current || g.max.CompareAndSwap(current, value) {
+			break
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package counters
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// Values are grouped into log-linear buckets: every power of two is
+// split into 1<<subBucketBits equal parts, so any recorded value is
+// reported with a relative error of at most 1/(1<<subBucketBits).
+const (
+	subBucketBits  = 3
+	subBucketCount = 1 << subBucketBits
+	bucketsCount   = (64-subBucketBits)*subBucketCount + subBucketCount
+)
+
+type histogramShard struct {
+	buckets [bucketsCount]atomic.Uint64
+	sum     atomic.Int64
+	_       [cacheLineSize]byte
+}
+
+type Histogram struct {
+	shards []histogramShard
+	mask   uint32
+}
+
+type HistogramSnapshot struct {
+	Count   uint64
+	Sum     int64
+	buckets []uint64
+}
+
+func NewHistogram() *Histogram {
+	size := nextPowerOfTwo(runtime.GOMAXPROCS(0))
+	return &Histogram{
+		shards: make([]histogramShard, size),
+		mask:   uint32(size - 1),
+	}
+}
+
+// Record accepts non-negative values, negative values are counted as zero.
+func (h *Histogram) Record(value int64) {
+	if value < 0 {
+		value = 0
+	}
+
+	shard := &h.shards[rand.Uint32()&h.mask]
+	shard.buckets[bucketIndex(uint64(value))].Add(1)
+	shard.sum.Add(value)
+}
+
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		buckets: make([]uint64, bucketsCount),
+	}
+
+	for idx := range h.shards {
+		shard := &h.shards[idx]
+		for bucket := range shard.buckets {
+			count := shard.buckets[bucket].Load()
+			snapshot.buckets[bucket] += count
+			snapshot.Count += count
+		}
+
+		snapshot.Sum += shard.sum.Load()
+	}
+
+	return snapshot
+}
+
+func (h *Histogram) Reset() {
+	for idx := range h.shards {
+		shard := &h.shards[idx]
+		for bucket := range shard.buckets {
+			shard.buckets[bucket].Store(0)
+		}
+
+		shard.sum.Store(0)
+	}
+}
+
+func (s HistogramSnapshot) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return float64(s.Sum) / float64(s.Count)
+}
+
+// Quantile returns an approximation of the q-th quantile (0 <= q <= 1).
+func (s HistogramSnapshot) Quantile(q float64) int64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	q = math.Max(0, math.Min(1, q))
+	rank := uint64(math.Ceil(q * float64(s.Count)))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+	for idx, count := range s.buckets {
+		seen += count
+		if seen >= rank {
+			return bucketValue(idx)
+		}
+	}
+
+	return bucketValue(len(s.buckets) - 1)
+}
+
+func (s HistogramSnapshot) Min() int64 {
+	for idx, count := range s.buckets {
+		if count != 0 {
+			return bucketValue(idx)
+		}
+	}
+
+	return 0
+}
+
+func (s HistogramSnapshot) Max() int64 {
+	for idx := len(s.buckets) - 1; idx >= 0; idx-- {
+		if s.buckets[idx] != 0 {
+			return bucketValue(idx)
+		}
+	}
+
+	return 0
+}
+
+// ForEachBucket calls action for every non-empty bucket in ascending order.
+func (s HistogramSnapshot) ForEachBucket(action func(lower, upper int64, count uint64)) {
+	for idx, count := range s.buckets {
+		if count == 0 {
+			continue
+		}
+
+		lower, upper := bucketBounds(idx)
+		action(lower, upper, count)
+	}
+}
+
+func bucketIndex(value uint64) int {
+	if value < subBucketCount {
+		return int(value)
+	}
+
+	shift := bits.Len64(value) - 1 - subBucketBits
+	mantissa := value >> shift
+	return (shift+1)*subBucketCount + int(mantissa-subBucketCount)
+}
+
+func bucketBounds(idx int) (int64, int64) {
+	if idx < subBucketCount {
+		return int64(idx), int64(idx)
+	}
+
+	shift := idx/subBucketCount - 1
+	mantissa := uint64(idx%subBucketCount + subBucketCount)
+	lower := mantissa << shift
+	upper := lower + (uint64(1) << shift) - 1
+	if upper > math.MaxInt64 {
+		upper = math.MaxInt64
+	}
+	if lower > math.MaxInt64 {
+		lower = math.MaxInt64
+	}
+
+	return int64(lower), int64(upper)
+}
+
+func bucketValue(idx int) int64 {
+	lower, upper := bucketBounds(idx)
+	return lower + (upper-lower)/2
+}
@@ -0,0 +1,116 @@
+package counters
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// go test -bench=. -benchmem .
+
+type MutexCounter struct {
+	value int32
+	mutex sync.Mutex
+}
+
+func (c *MutexCounter) Increment(int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.value++
+}
+
+type AtomicCounter struct {
+	value atomic.Int32
+}
+
+func (c *AtomicCounter) Increment(int) {
+	c.value.Add(1)
+}
+
+type ShardedAtomicCounter struct {
+	shards [10]AtomicCounter
+}
+
+func (c *ShardedAtomicCounter) Increment(idx int) {
+	c.shards[idx%10].value.Add(1)
+}
+
+type paddedShardedCounter struct {
+	counter *ShardedCounter
+}
+
+func (c paddedShardedCounter) Increment(idx int) {
+	c.counter.AddAt(idx, 1)
+}
+
+type randomShardedCounter struct {
+	counter *ShardedCounter
+}
+
+func (c randomShardedCounter) Increment(int) {
+	c.counter.Increment()
+}
+
+type incrementer interface {
+	Increment(int)
+}
+
+func runParallel(b *testing.B, counter incrementer) {
+	goroutinesNumber := runtime.GOMAXPROCS(0)
+
+	wg := sync.WaitGroup{}
+	wg.Add(goroutinesNumber)
+
+	b.ResetTimer()
+	for i := 0; i < goroutinesNumber; i++ {
+		go func(idx int) {
+			defer wg.Done()
+			for j := 0; j < b.N; j++ {
+				counter.Increment(idx)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkMutexCounter(b *testing.B) {
+	runParallel(b, &MutexCounter{})
+}
+
+func BenchmarkAtomicCounter(b *testing.B) {
+	runParallel(b, &AtomicCounter{})
+}
+
+func BenchmarkShardedAtomicCounter(b *testing.B) {
+	runParallel(b, &ShardedAtomicCounter{})
+}
+
+func BenchmarkPaddedShardedCounter(b *testing.B) {
+	runParallel(b, paddedShardedCounter{counter: NewShardedCounter()})
+}
+
+func BenchmarkRandomShardedCounter(b *testing.B) {
+	runParallel(b, randomShardedCounter{counter: NewShardedCounter()})
+}
+
+func BenchmarkGauge(b *testing.B) {
+	gauge := NewGauge()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			gauge.Add(1)
+		}
+	})
+}
+
+func BenchmarkHistogram(b *testing.B) {
+	histogram := NewHistogram()
+	b.RunParallel(func(pb *testing.PB) {
+		var value int64
+		for pb.Next() {
+			histogram.Record(value)
+			value++
+		}
+	})
+}
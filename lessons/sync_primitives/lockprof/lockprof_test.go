@@ -0,0 +1,232 @@
+package lockprof
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+// go test -bench=. -benchmem .
+
+func TestMutexWithoutContention(t *testing.T) {
+	registry := NewRegistry()
+	mutex := registry.Mutex("cache")
+
+	for i := 0; i < 10; i++ {
+		mutex.Lock()
+		mutex.Unlock()
+	}
+
+	assert.True(t, mutex.TryLock())
+	mutex.Unlock()
+
+	snapshot := mutex.Stats().Snapshot()
+	assert.Equal(t, "cache", snapshot.Name)
+	assert.Equal(t, int64(11), snapshot.Acquisitions)
+	assert.Zero(t, snapshot.Contentions)
+	assert.Zero(t, snapshot.Wait.Count)
+	assert.Equal(t, uint64(11), snapshot.Hold.Count)
+}
+
+func TestMutexWithContention(t *testing.T) {
+	registry := NewRegistry()
+	mutex := registry.Mutex("storage")
+
+	mutex.Lock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mutex.Lock()
+		mutex.Unlock()
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	mutex.Unlock()
+	<-done
+
+	snapshot := mutex.Stats().Snapshot()
+	assert.Equal(t, int64(2), snapshot.Acquisitions)
+	assert.Equal(t, int64(1), snapshot.Contentions)
+	assert.Equal(t, uint64(1), snapshot.Wait.Count)
+	assert.GreaterOrEqual(t, snapshot.Wait.Max(), int64(15*time.Millisecond))
+	assert.GreaterOrEqual(t, snapshot.Hold.Max(), int64(15*time.Millisecond))
+}
+
+func TestRWMutex(t *testing.T) {
+	registry := NewRegistry()
+	mutex := registry.RWMutex("config")
+
+	mutex.RLock()
+	mutex.RLock()
+	assert.False(t, mutex.TryLock())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mutex.Lock()
+		mutex.Unlock()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	mutex.RUnlock()
+	mutex.RUnlock()
+	<-done
+
+	locker := mutex.RLocker()
+	locker.Lock()
+	locker.Unlock()
+
+	snapshot := mutex.Stats().Snapshot()
+	assert.Equal(t, int64(4), snapshot.Acquisitions)
+	assert.Equal(t, int64(1), snapshot.Contentions)
+	assert.Equal(t, uint64(1), snapshot.Hold.Count)
+}
+
+func TestRegistryTopContended(t *testing.T) {
+	registry := NewRegistry()
+
+	hot := registry.Mutex("hot")
+	warm := registry.Mutex("warm")
+	cold := registry.Mutex("cold")
+	shared := registry.Mutex("hot")
+
+	contend(hot, 8)
+	contend(shared, 8)
+	contend(warm, 4)
+	cold.Lock()
+	cold.Unlock()
+
+	top := registry.TopContended(2)
+	require.Len(t, top, 2)
+	assert.Equal(t, "hot", top[0].Name)
+	assert.Equal(t, "warm", top[1].Name)
+	assert.Greater(t, top[0].Contentions, top[1].Contentions)
+
+	assert.Len(t, registry.Snapshot(), 3)
+
+	registry.Reset()
+	for _, snapshot := range registry.Snapshot() {
+		assert.Zero(t, snapshot.Acquisitions)
+		assert.Zero(t, snapshot.Contentions)
+	}
+}
+
+func TestHoldSampling(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetHoldSampling(1000000)
+	mutex := registry.Mutex("sampled")
+
+	for i := 0; i < 100; i++ {
+		mutex.Lock()
+		mutex.Unlock()
+	}
+
+	snapshot := mutex.Stats().Snapshot()
+	assert.Equal(t, int64(100), snapshot.Acquisitions)
+	assert.Less(t, snapshot.Hold.Count, uint64(100))
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	DefaultRegistry.Reset()
+
+	mutex := NewMutex("default")
+	mutex.Lock()
+	mutex.Unlock()
+
+	rwMutex := NewRWMutex("default")
+	rwMutex.RLock()
+	rwMutex.RUnlock()
+
+	assert.Equal(t, int64(2), DefaultRegistry.Stats("default").Snapshot().Acquisitions)
+}
+
+func TestZeroValue(t *testing.T) {
+	var mutex Mutex
+	mutex.Lock()
+	assert.False(t, mutex.TryLock())
+	mutex.Unlock()
+	assert.True(t, mutex.TryLock())
+	mutex.Unlock()
+
+	var rwMutex RWMutex
+	rwMutex.RLock()
+	assert.True(t, rwMutex.TryRLock())
+	rwMutex.RUnlock()
+	rwMutex.RUnlock()
+	rwMutex.Lock()
+	rwMutex.Unlock()
+
+	contend(&mutex, 2)
+
+	assert.Nil(t, mutex.Stats())
+	assert.Nil(t, rwMutex.Stats())
+	assert.Zero(t, mutex.Stats().Snapshot())
+}
+
+func contend(mutex *Mutex, times int) {
+	for i := 0; i < times; i++ {
+		mutex.Lock()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			mutex.Lock()
+			mutex.Unlock()
+		}()
+
+		time.Sleep(time.Millisecond)
+		mutex.Unlock()
+		<-done
+	}
+}
+
+func BenchmarkMutex(b *testing.B) {
+	var number int
+	var mutex sync.Mutex
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mutex.Lock()
+			number++
+			mutex.Unlock()
+		}
+	})
+}
+
+func BenchmarkProfiledMutex(b *testing.B) {
+	var number int
+	mutex := NewRegistry().Mutex("bench")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mutex.Lock()
+			number++
+			mutex.Unlock()
+		}
+	})
+}
+
+func BenchmarkProfiledMutexWithoutContention(b *testing.B) {
+	var number int
+	mutex := NewRegistry().Mutex("bench")
+	for i := 0; i < b.N; i++ {
+		mutex.Lock()
+		number++
+		mutex.Unlock()
+	}
+}
+
+func BenchmarkSampledProfiledMutexWithoutContention(b *testing.B) {
+	var number int
+	registry := NewRegistry()
+	registry.SetHoldSampling(64)
+	mutex := registry.Mutex("bench")
+	for i := 0; i < b.N; i++ {
+		mutex.Lock()
+		number++
+		mutex.Unlock()
+	}
+}
@@ -0,0 +1,143 @@
+package lockprof
+
+import (
+	"sync"
+	"time"
+)
+
+// Mutex is a sync.Mutex that reports its contention to a Registry.
+// Uncontended acquisitions take the TryLock fast path and don't read
+// the clock for the wait time, so the overhead stays small.
+// The zero value is an unlocked mutex that isn't attached to any Registry
+// and records nothing, its Stats are nil.
+type Mutex struct {
+	mutex    sync.Mutex
+	stats    *Stats
+	lockedAt time.Time
+}
+
+func (m *Mutex) Lock() {
+	if m.mutex.TryLock() {
+		m.stats.acquired()
+	} else {
+		start := time.Now()
+		m.mutex.Lock()
+		m.stats.contended(time.Since(start))
+	}
+
+	m.startHold()
+}
+
+func (m *Mutex) TryLock() bool {
+	if !m.mutex.TryLock() {
+		return false
+	}
+
+	m.stats.acquired()
+	m.startHold()
+	return true
+}
+
+func (m *Mutex) Unlock() {
+	if !m.lockedAt.IsZero() {
+		m.stats.held(time.Since(m.lockedAt))
+	}
+
+	m.mutex.Unlock()
+}
+
+func (m *Mutex) Stats() *Stats {
+	return m.stats
+}
+
+func (m *Mutex) startHold() {
+	if m.stats.sampleHold() {
+		m.lockedAt = time.Now()
+	} else {
+		m.lockedAt = time.Time{}
+	}
+}
+
+// RWMutex is a sync.RWMutex that reports its contention to a Registry.
+// Readers are anonymous, so hold time is recorded for writers only.
+// As with Mutex, the zero value is usable and records nothing.
+type RWMutex struct {
+	mutex    sync.RWMutex
+	stats    *Stats
+	lockedAt time.Time
+}
+
+func (m *RWMutex) Lock() {
+	if m.mutex.TryLock() {
+		m.stats.acquired()
+	} else {
+		start := time.Now()
+		m.mutex.Lock()
+		m.stats.contended(time.Since(start))
+	}
+
+	m.startHold()
+}
+
+func (m *RWMutex) TryLock() bool {
+	if !m.mutex.TryLock() {
+		return false
+	}
+
+	m.stats.acquired()
+	m.startHold()
+	return true
+}
+
+func (m *RWMutex) Unlock() {
+	if !m.lockedAt.IsZero() {
+		m.stats.held(time.Since(m.lockedAt))
+	}
+
+	m.mutex.Unlock()
+}
+
+func (m *RWMutex) RLock() {
+	if m.mutex.TryRLock() {
+		m.stats.acquired()
+		return
+	}
+
+	start := time.Now()
+	m.mutex.RLock()
+	m.stats.contended(time.Since(start))
+}
+
+func (m *RWMutex) TryRLock() bool {
+	if !m.mutex.TryRLock() {
+		return false
+	}
+
+	m.stats.acquired()
+	return true
+}
+
+func (m *RWMutex) RUnlock() {
+	m.mutex.RUnlock()
+}
+
+func (m *RWMutex) RLocker() sync.Locker {
+	return (*rlocker)(m)
+}
+
+func (m *RWMutex) Stats() *Stats {
+	return m.stats
+}
+
+func (m *RWMutex) startHold() {
+	if m.stats.sampleHold() {
+		m.lockedAt = time.Now()
+	} else {
+		m.lockedAt = time.Time{}
+	}
+}
+
+type rlocker RWMutex
+
+func (r *rlocker) Lock()   { (*RWMutex)(r).RLock() }
+func (r *rlocker) Unlock() { (*RWMutex)(r).RUnlock() }
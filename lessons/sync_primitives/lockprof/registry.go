@@ -0,0 +1,203 @@
+package lockprof
+
+import (
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang_course/lessons/sync_primitives/counters"
+)
+
+// Stats is shared by all locks registered under the same name,
+// so per-instance locks of one type can be reported together.
+type Stats struct {
+	name         string
+	acquisitions *counters.ShardedCounter
+	contentions  atomic.Int64
+	wait         *counters.Histogram
+	hold         *counters.Histogram
+	holdSampling *atomic.Uint32
+}
+
+type StatsSnapshot struct {
+	Name         string
+	Acquisitions int64
+	Contentions  int64
+	Wait         counters.HistogramSnapshot
+	Hold         counters.HistogramSnapshot
+}
+
+func newStats(name string, holdSampling *atomic.Uint32) *Stats {
+	return &Stats{
+		name:         name,
+		acquisitions: counters.NewShardedCounter(),
+		wait:         counters.NewHistogram(),
+		hold:         counters.NewHistogram(),
+		holdSampling: holdSampling,
+	}
+}
+
+// The recording methods accept a nil receiver, which is what the zero
+// value of Mutex and RWMutex has: such locks work but aren't profiled.
+
+func (s *Stats) sampleHold() bool {
+	if s == nil {
+		return false
+	}
+
+	every := s.holdSampling.Load()
+	return every <= 1 || rand.Uint32()%every == 0
+}
+
+func (s *Stats) acquired() {
+	if s == nil {
+		return
+	}
+
+	s.acquisitions.Increment()
+}
+
+func (s *Stats) contended(wait time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.acquisitions.Increment()
+	s.contentions.Add(1)
+	s.wait.Record(int64(wait))
+}
+
+func (s *Stats) held(hold time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.hold.Record(int64(hold))
+}
+
+func (s *Stats) Snapshot() StatsSnapshot {
+	if s == nil {
+		return StatsSnapshot{}
+	}
+
+	return StatsSnapshot{
+		Name:         s.name,
+		Acquisitions: s.acquisitions.Load(),
+		Contentions:  s.contentions.Load(),
+		Wait:         s.wait.Snapshot(),
+		Hold:         s.hold.Snapshot(),
+	}
+}
+
+func (s *Stats) reset() {
+	s.acquisitions.Reset()
+	s.contentions.Store(0)
+	s.wait.Reset()
+	s.hold.Reset()
+}
+
+type Registry struct {
+	mutex        sync.Mutex
+	stats        map[string]*Stats
+	holdSampling atomic.Uint32
+}
+
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{
+		stats: make(map[string]*Stats),
+	}
+}
+
+// SetHoldSampling makes locks measure the hold time of one in "every"
+// acquisitions. Reading the clock is the most expensive part of the
+// profiling, so sampling keeps the fast path cheap on busy locks.
+// Wait time is measured only for contended acquisitions and isn't sampled.
+func (r *Registry) SetHoldSampling(every int) {
+	if every < 1 {
+		every = 1
+	}
+
+	r.holdSampling.Store(uint32(every))
+}
+
+func (r *Registry) Mutex(name string) *Mutex {
+	return &Mutex{stats: r.Stats(name)}
+}
+
+func (r *Registry) RWMutex(name string) *RWMutex {
+	return &RWMutex{stats: r.Stats(name)}
+}
+
+// Stats returns the statistics of name, creating them on first use.
+func (r *Registry) Stats(name string) *Stats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stats, ok := r.stats[name]
+	if !ok {
+		stats = newStats(name, &r.holdSampling)
+		r.stats[name] = stats
+	}
+
+	return stats
+}
+
+func (r *Registry) Snapshot() []StatsSnapshot {
+	r.mutex.Lock()
+	all := make([]*Stats, 0, len(r.stats))
+	for _, stats := range r.stats {
+		all = append(all, stats)
+	}
+	r.mutex.Unlock()
+
+	snapshots := make([]StatsSnapshot, 0, len(all))
+	for _, stats := range all {
+		snapshots = append(snapshots, stats.Snapshot())
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+
+	return snapshots
+}
+
+// TopContended returns up to limit locks ordered by contention count,
+// locks with equal counts are ordered by total wait time.
+func (r *Registry) TopContended(limit int) []StatsSnapshot {
+	snapshots := r.Snapshot()
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].Contentions != snapshots[j].Contentions {
+			return snapshots[i].Contentions > snapshots[j].Contentions
+		}
+
+		return snapshots[i].Wait.Sum > snapshots[j].Wait.Sum
+	})
+
+	if limit >= 0 && len(snapshots) > limit {
+		snapshots = snapshots[:limit]
+	}
+
+	return snapshots
+}
+
+func (r *Registry) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, stats := range r.stats {
+		stats.reset()
+	}
+}
+
+func NewMutex(name string) *Mutex {
+	return DefaultRegistry.Mutex(name)
+}
+
+func NewRWMutex(name string) *RWMutex {
+	return DefaultRegistry.RWMutex(name)
+}
@@ -0,0 +1,49 @@
+package singleflight
+
+// CachedGroup checks a cache before and inside the flight, like the
+// fast and slow paths of sync.Once. The second check catches callers that
+// missed the cache right before the previous flight filled it.
+type CachedGroup[K comparable, V any] struct {
+	group  Group[K, V]
+	lookup func(K) (V, bool)
+}
+
+func NewCachedGroup[K comparable, V any](lookup func(K) (V, bool)) *CachedGroup[K, V] {
+	return &CachedGroup[K, V]{lookup: lookup}
+}
+
+// Do returns the cached value if there is one, otherwise it runs fn
+// once for all concurrent callers. fn is expected to fill the cache.
+func (g *CachedGroup[K, V]) Do(key K, fn func() (V, error)) (V, error, bool) {
+	if value, ok := g.lookup(key); ok {
+		return value, nil, false
+	}
+
+	return g.group.Do(key, func() (V, error) {
+		if value, ok := g.lookup(key); ok {
+			return value, nil
+		}
+
+		return fn()
+	})
+}
+
+func (g *CachedGroup[K, V]) DoChan(key K, fn func() (V, error)) <-chan Result[V] {
+	if value, ok := g.lookup(key); ok {
+		ch := make(chan Result[V], 1)
+		ch <- Result[V]{Val: value}
+		return ch
+	}
+
+	return g.group.DoChan(key, func() (V, error) {
+		if value, ok := g.lookup(key); ok {
+			return value, nil
+		}
+
+		return fn()
+	})
+}
+
+func (g *CachedGroup[K, V]) Forget(key K) {
+	g.group.Forget(key)
+}
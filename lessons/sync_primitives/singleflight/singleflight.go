@@ -0,0 +1,156 @@
+package singleflight
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+
+	"golang_course/lessons/goroutines_and_scheduler/safe"
+)
+
+// PanicError is the Err of every waiter when the shared function panics.
+type PanicError = safe.PanicError
+
+// ErrGoexit is the Err of DoChan waiters when the shared function
+// calls runtime.Goexit.
+var ErrGoexit = safe.ErrGoexit
+
+type Result[V any] struct {
+	Val    V
+	Err    error
+	Shared bool
+}
+
+type call[V any] struct {
+	done   bool // guarded by Group.mutex
+	val    V
+	err    error
+	dups   int
+	shared bool
+	chans  []chan<- Result[V]
+
+	// panicked and goexit are set only by run, so an error returned by
+	// fn is passed through even if it wraps a *PanicError or ErrGoexit.
+	panicked *PanicError
+	goexit   bool
+}
+
+// Group deduplicates concurrent calls with the same key: only the
+// first caller runs the function, the others wait for it on a condition
+// variable and share its result. The zero value is ready to use.
+type Group[K comparable, V any] struct {
+	mutex sync.Mutex
+	cond  sync.Cond
+	calls map[K]*call[V]
+}
+
+func (g *Group[K, V]) lazyInit() {
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+		g.cond.L = &g.mutex
+	}
+}
+
+// Do runs fn once for all concurrent callers of key. The shared flag
+// reports whether the result was given to more than one caller. If fn
+// panics, every caller panics with a *PanicError, if fn calls
+// runtime.Goexit, every caller's goroutine exits.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mutex.Lock()
+	g.lazyInit()
+
+	c, ok := g.calls[key]
+	if ok {
+		c.dups++
+		for !c.done {
+			g.cond.Wait() // wakes up on every finished call, so the loop checks its own
+		}
+
+		g.mutex.Unlock()
+	} else {
+		c = new(call[V])
+		g.calls[key] = c
+		g.mutex.Unlock()
+
+		g.run(c, key, fn) // doesn't return on runtime.Goexit
+	}
+
+	if c.panicked != nil {
+		c.panicked.Repanic()
+	} else if c.goexit {
+		runtime.Goexit()
+	}
+
+	return c.val, c.err, c.shared
+}
+
+// DoChan is like Do but returns a channel that receives the result,
+// fn runs in a new goroutine. A panic or runtime.Goexit in fn doesn't
+// propagate, it is received as a *PanicError or ErrGoexit in Err.
+func (g *Group[K, V]) DoChan(key K, fn func() (V, error)) <-chan Result[V] {
+	ch := make(chan Result[V], 1)
+
+	g.mutex.Lock()
+	g.lazyInit()
+
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		c.chans = append(c.chans, ch)
+		g.mutex.Unlock()
+		return ch
+	}
+
+	c := &call[V]{chans: []chan<- Result[V]{ch}}
+	g.calls[key] = c
+	g.mutex.Unlock()
+
+	go g.run(c, key, fn)
+
+	return ch
+}
+
+// Forget makes the next call of key run the function again instead of
+// waiting for the one that is in flight.
+func (g *Group[K, V]) Forget(key K) {
+	g.mutex.Lock()
+	delete(g.calls, key)
+	g.mutex.Unlock()
+}
+
+func (g *Group[K, V]) run(c *call[V], key K, fn func() (V, error)) {
+	returned := false
+	defer func() {
+		if !returned {
+			c.goexit = true
+			c.err = ErrGoexit
+		}
+
+		g.finish(c, key)
+	}()
+
+	if err := safe.Call(func() { c.val, c.err = fn() }); err != nil {
+		errors.As(err, &c.panicked)
+		c.err = err
+	}
+
+	returned = true
+}
+
+// finish wakes up the waiters, the result is sent to channels without
+// blocking because they are buffered.
+func (g *Group[K, V]) finish(c *call[V], key K) {
+	g.mutex.Lock()
+	c.done = true
+	c.shared = c.dups > 0
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+
+	chans := c.chans
+	g.mutex.Unlock()
+
+	g.cond.Broadcast()
+	for _, ch := range chans {
+		ch <- Result[V]{Val: c.val, Err: c.err, Shared: c.shared}
+	}
+}
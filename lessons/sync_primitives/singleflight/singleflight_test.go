@@ -0,0 +1,364 @@
+package singleflight
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/lessons/goroutines_and_scheduler/safe"
+)
+
+// go test -v -race .
+
+func TestDo(t *testing.T) {
+	var group Group[string, int]
+	value, err, shared := group.Do("key", func() (int, error) {
+		return 42, nil
+	})
+
+	assert.Equal(t, 42, value)
+	assert.NoError(t, err)
+	assert.False(t, shared)
+
+	expectedErr := errors.New("error")
+	_, err, _ = group.Do("key", func() (int, error) {
+		return 0, expectedErr
+	})
+
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestDoDeduplication(t *testing.T) {
+	var group Group[string, int]
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fn := func() (int, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+
+		<-release
+		return 42, nil
+	}
+
+	const goroutinesNumber = 10
+
+	wg := sync.WaitGroup{}
+	wg.Add(goroutinesNumber)
+	for i := 0; i < goroutinesNumber; i++ {
+		go func() {
+			defer wg.Done()
+			value, err, shared := group.Do("key", fn)
+			assert.Equal(t, 42, value)
+			assert.NoError(t, err)
+			assert.True(t, shared)
+		}()
+	}
+
+	<-started
+	waitForDups(&group, "key", goroutinesNumber-1)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDoChan(t *testing.T) {
+	var group Group[int, string]
+	release := make(chan struct{})
+
+	first := group.DoChan(1, func() (string, error) {
+		<-release
+		return "value", nil
+	})
+
+	second := group.DoChan(1, func() (string, error) {
+		return "unexpected", nil
+	})
+
+	close(release)
+
+	for _, ch := range []<-chan Result[string]{first, second} {
+		result := <-ch
+		assert.Equal(t, "value", result.Val)
+		assert.NoError(t, result.Err)
+		assert.True(t, result.Shared)
+	}
+}
+
+func TestForget(t *testing.T) {
+	var group Group[string, int]
+	release := make(chan struct{})
+
+	first := group.DoChan("key", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	group.Forget("key")
+
+	value, _, shared := group.Do("key", func() (int, error) {
+		return 2, nil
+	})
+
+	assert.Equal(t, 2, value)
+	assert.False(t, shared)
+
+	close(release)
+	assert.Equal(t, 1, (<-first).Val)
+}
+
+func TestPanicIsPassedToEveryWaiter(t *testing.T) {
+	var group Group[string, int]
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	const goroutinesNumber = 5
+
+	panics := make(chan any, goroutinesNumber)
+	wg := sync.WaitGroup{}
+	wg.Add(goroutinesNumber)
+	for i := 0; i < goroutinesNumber; i++ {
+		go func() {
+			defer wg.Done()
+			defer func() {
+				panics <- recover()
+			}()
+
+			_, _, _ = group.Do("key", func() (int, error) {
+				close(started)
+				<-release
+				panic("boom")
+			})
+		}()
+	}
+
+	<-started
+	waitForDups(&group, "key", goroutinesNumber-1)
+	close(release)
+	wg.Wait()
+	close(panics)
+
+	for value := range panics {
+		var panicErr *PanicError
+		require.ErrorAs(t, value.(error), &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+		assert.NotEmpty(t, panicErr.Stack)
+	}
+}
+
+func TestGoexitIsPassedToEveryWaiter(t *testing.T) {
+	var group Group[string, int]
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	const goroutinesNumber = 5
+
+	var returned atomic.Int32
+	wg := sync.WaitGroup{}
+	wg.Add(goroutinesNumber)
+	for i := 0; i < goroutinesNumber; i++ {
+		go func() {
+			defer wg.Done()
+			_, _, _ = group.Do("key", func() (int, error) {
+				close(started)
+				<-release
+				runtime.Goexit()
+				return 0, nil
+			})
+
+			returned.Add(1)
+		}()
+	}
+
+	<-started
+	waitForDups(&group, "key", goroutinesNumber-1)
+	close(release)
+	wg.Wait()
+
+	assert.Zero(t, returned.Load())
+}
+
+func TestDoChanReceivesPanic(t *testing.T) {
+	var group Group[string, int]
+	release := make(chan struct{})
+
+	first := group.DoChan("key", func() (int, error) {
+		<-release
+		panic("boom")
+	})
+
+	second := group.DoChan("key", func() (int, error) {
+		return 0, nil
+	})
+
+	close(release)
+
+	for _, ch := range []<-chan Result[int]{first, second} {
+		select {
+		case result := <-ch:
+			var panicErr *PanicError
+			require.ErrorAs(t, result.Err, &panicErr)
+			assert.Equal(t, "boom", panicErr.Value)
+			assert.True(t, result.Shared)
+		case <-time.After(time.Second):
+			t.Fatal("waiter didn't get the panic")
+		}
+	}
+}
+
+func TestDoChanReceivesGoexit(t *testing.T) {
+	var group Group[string, int]
+	release := make(chan struct{})
+
+	first := group.DoChan("key", func() (int, error) {
+		<-release
+		runtime.Goexit()
+		return 0, nil
+	})
+
+	second := group.DoChan("key", func() (int, error) {
+		return 0, nil
+	})
+
+	close(release)
+
+	for _, ch := range []<-chan Result[int]{first, second} {
+		select {
+		case result := <-ch:
+			assert.ErrorIs(t, result.Err, ErrGoexit)
+			assert.True(t, result.Shared)
+		case <-time.After(time.Second):
+			t.Fatal("waiter didn't get runtime.Goexit")
+		}
+	}
+}
+
+func TestPanicIsPassedToDoAndDoChanWaiters(t *testing.T) {
+	var group Group[string, int]
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	recovered := make(chan any, 1)
+	go func() {
+		defer func() {
+			recovered <- recover()
+		}()
+
+		_, _, _ = group.Do("key", func() (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+
+	<-started
+	ch := group.DoChan("key", func() (int, error) {
+		return 0, nil
+	})
+
+	close(release)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, (<-ch).Err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Same(t, panicErr, <-recovered)
+}
+
+func TestReturnedPanicErrorIsNotRaised(t *testing.T) {
+	var group Group[string, int]
+
+	handled := safe.Call(func() {
+		panic("handled")
+	})
+
+	value, err, _ := group.Do("key", func() (int, error) {
+		return 1, fmt.Errorf("job failed: %w", handled)
+	})
+
+	assert.Equal(t, 1, value)
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "handled", panicErr.Value)
+
+	_, err, _ = group.Do("key", func() (int, error) {
+		return 0, ErrGoexit
+	})
+
+	assert.ErrorIs(t, err, ErrGoexit)
+
+	result := <-group.DoChan("key", func() (int, error) {
+		return 0, handled
+	})
+
+	assert.Same(t, handled, result.Err)
+}
+
+func TestCachedGroup(t *testing.T) {
+	var mutex sync.Mutex
+	cache := map[string]int{}
+	lookup := func(key string) (int, bool) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		value, ok := cache[key]
+		return value, ok
+	}
+
+	group := NewCachedGroup(lookup)
+
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		cache["key"] = 42
+		return 42, nil
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		go func() {
+			defer wg.Done()
+			value, err, _ := group.Do("key", fn)
+			assert.Equal(t, 42, value)
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+
+	result := <-group.DoChan("key", fn)
+	assert.Equal(t, 42, result.Val)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func waitForDups[K comparable, V any](group *Group[K, V], key K, dups int) {
+	for {
+		group.mutex.Lock()
+		c, ok := group.calls[key]
+		done := !ok || c.dups >= dups
+		group.mutex.Unlock()
+
+		if done {
+			return
+		}
+
+		runtime.Gosched()
+	}
+}
@@ -0,0 +1,145 @@
+package snapshot
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// go test -bench=. -cpu=1,2,4,8 .
+
+type settings struct {
+	limit   int64
+	timeout int64
+}
+
+func BenchmarkRWMutexRead(b *testing.B) {
+	var mutex sync.RWMutex
+	value := settings{limit: 10, timeout: 20}
+
+	b.RunParallel(func(pb *testing.PB) {
+		var sum int64
+		for pb.Next() {
+			mutex.RLock()
+			sum += value.limit + value.timeout
+			mutex.RUnlock()
+		}
+		_ = sum
+	})
+}
+
+func BenchmarkSnapshotRead(b *testing.B) {
+	snapshot := New(&settings{limit: 10, timeout: 20})
+
+	b.RunParallel(func(pb *testing.PB) {
+		var sum int64
+		for pb.Next() {
+			value := snapshot.Load()
+			sum += value.limit + value.timeout
+		}
+		_ = sum
+	})
+}
+
+func BenchmarkSeqLockRead(b *testing.B) {
+	lock := NewSeqLock(settings{limit: 10, timeout: 20})
+
+	b.RunParallel(func(pb *testing.PB) {
+		var sum int64
+		for pb.Next() {
+			value := lock.Load()
+			sum += value.limit + value.timeout
+		}
+		_ = sum
+	})
+}
+
+func BenchmarkRWMutexReadWithWriter(b *testing.B) {
+	var mutex sync.RWMutex
+	value := settings{limit: 10, timeout: 20}
+
+	stop := runWriter(func() {
+		mutex.Lock()
+		value.limit++
+		mutex.Unlock()
+	})
+	defer stop()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var sum int64
+		for pb.Next() {
+			mutex.RLock()
+			sum += value.limit + value.timeout
+			mutex.RUnlock()
+		}
+		_ = sum
+	})
+}
+
+func BenchmarkSnapshotReadWithWriter(b *testing.B) {
+	snapshot := New(&settings{limit: 10, timeout: 20})
+
+	stop := runWriter(func() {
+		snapshot.Update(func(current *settings) *settings {
+			next := *current
+			next.limit++
+			return &next
+		})
+	})
+	defer stop()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var sum int64
+		for pb.Next() {
+			value := snapshot.Load()
+			sum += value.limit + value.timeout
+		}
+		_ = sum
+	})
+}
+
+func BenchmarkSeqLockReadWithWriter(b *testing.B) {
+	lock := NewSeqLock(settings{limit: 10, timeout: 20})
+
+	stop := runWriter(func() {
+		lock.Update(func(value settings) settings {
+			value.limit++
+			return value
+		})
+	})
+	defer stop()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var sum int64
+		for pb.Next() {
+			value := lock.Load()
+			sum += value.limit + value.timeout
+		}
+		_ = sum
+	})
+}
+
+func runWriter(write func()) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(10 * time.Microsecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				write()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
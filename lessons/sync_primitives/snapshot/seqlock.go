@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// SeqLock stores a small value without pointers. Writers bump the
+// sequence to an odd number while they copy the value, readers retry
+// until they see the same even sequence before and after the copy.
+// The value is kept in atomic words, so optimistic reads aren't data races.
+type SeqLock[T any] struct {
+	mutex    sync.Mutex
+	sequence atomic.Uint64
+	words    []atomic.Uint64
+	aligned  bool
+}
+
+func NewSeqLock[T any](value T) *SeqLock[T] {
+	if typ := reflect.TypeFor[T](); hasPointers(typ) {
+		panic("snapshot: SeqLock value must not contain pointers, got " + typ.String())
+	}
+
+	var word uint64
+	lock := &SeqLock[T]{
+		words:   make([]atomic.Uint64, (unsafe.Sizeof(value)+7)/8),
+		aligned: unsafe.Sizeof(value)%8 == 0 && unsafe.Alignof(value) >= unsafe.Alignof(word),
+	}
+
+	lock.store(&value)
+	return lock
+}
+
+func (l *SeqLock[T]) Load() T {
+	var value T
+	for {
+		sequence := l.sequence.Load()
+		if sequence&1 == 1 {
+			runtime.Gosched()
+			continue
+		}
+
+		l.load(&value)
+		if l.sequence.Load() == sequence {
+			return value
+		}
+	}
+}
+
+func (l *SeqLock[T]) Store(value T) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.sequence.Add(1)
+	l.store(&value)
+	l.sequence.Add(1)
+}
+
+func (l *SeqLock[T]) Update(update func(T) T) T {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var value T
+	l.load(&value)
+	value = update(value)
+
+	l.sequence.Add(1)
+	l.store(&value)
+	l.sequence.Add(1)
+
+	return value
+}
+
+func (l *SeqLock[T]) load(value *T) {
+	if l.aligned {
+		words := unsafe.Slice((*uint64)(unsafe.Pointer(value)), len(l.words))
+		for idx := range l.words {
+			words[idx] = l.words[idx].Load()
+		}
+
+		return
+	}
+
+	bytes := unsafe.Slice((*byte)(unsafe.Pointer(value)), unsafe.Sizeof(*value))
+	for idx := range l.words {
+		word := l.words[idx].Load()
+		copy(bytes[idx*8:], (*[8]byte)(unsafe.Pointer(&word))[:])
+	}
+}
+
+func (l *SeqLock[T]) store(value *T) {
+	if l.aligned {
+		words := unsafe.Slice((*uint64)(unsafe.Pointer(value)), len(l.words))
+		for idx := range l.words {
+			l.words[idx].Store(words[idx])
+		}
+
+		return
+	}
+
+	bytes := unsafe.Slice((*byte)(unsafe.Pointer(value)), unsafe.Sizeof(*value))
+	for idx := range l.words {
+		var word uint64
+		copy((*[8]byte)(unsafe.Pointer(&word))[:], bytes[idx*8:])
+		l.words[idx].Store(word)
+	}
+}
+
+func hasPointers(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.String, reflect.Slice,
+		reflect.Map, reflect.Chan, reflect.Func, reflect.Interface:
+		return true
+	case reflect.Array:
+		return typ.Len() > 0 && hasPointers(typ.Elem())
+	case reflect.Struct:
+		for idx := 0; idx < typ.NumField(); idx++ {
+			if hasPointers(typ.Field(idx).Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,39 @@
+package snapshot
+
+import (
+	"sync/atomic"
+)
+
+// Snapshot keeps an immutable value behind an atomic.Pointer.
+// Readers never block, writers publish a new copy (RCU style),
+// so values returned by Load must never be modified.
+type Snapshot[T any] struct {
+	pointer atomic.Pointer[T]
+}
+
+func New[T any](value *T) *Snapshot[T] {
+	snapshot := &Snapshot[T]{}
+	snapshot.pointer.Store(value)
+	return snapshot
+}
+
+func (s *Snapshot[T]) Load() *T {
+	return s.pointer.Load()
+}
+
+func (s *Snapshot[T]) Store(value *T) {
+	s.pointer.Store(value)
+}
+
+// Update applies a copy-on-write change: update receives the current
+// value and must return a new one without touching the old value.
+// update may be called several times when writers race each other.
+func (s *Snapshot[T]) Update(update func(*T) *T) *T {
+	for {
+		current := s.pointer.Load()
+		next := update(current)
+		if s.pointer.CompareAndSwap(current, next) {
+			return next
+		}
+	}
+}
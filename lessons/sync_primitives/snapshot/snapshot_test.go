@@ -0,0 +1,121 @@
+package snapshot
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v -race .
+
+type config struct {
+	version  int
+	backends []string
+}
+
+func TestSnapshotUpdate(t *testing.T) {
+	snapshot := New(&config{})
+
+	const writersNumber = 8
+	const updatesNumber = 100
+
+	wg := sync.WaitGroup{}
+	wg.Add(writersNumber * 2)
+	for i := 0; i < writersNumber; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updatesNumber; j++ {
+				snapshot.Update(func(current *config) *config {
+					return &config{
+						version:  current.version + 1,
+						backends: append([]string{"backend"}, current.backends...),
+					}
+				})
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updatesNumber; j++ {
+				current := snapshot.Load()
+				assert.Equal(t, current.version, len(current.backends))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, writersNumber*updatesNumber, snapshot.Load().version)
+
+	snapshot.Store(&config{version: -1})
+	assert.Equal(t, -1, snapshot.Load().version)
+}
+
+type point struct {
+	x, y, z int64
+	tag     [3]byte
+}
+
+func TestSeqLock(t *testing.T) {
+	lock := NewSeqLock(point{tag: [3]byte{'a', 'b', 'c'}})
+	assert.Equal(t, point{tag: [3]byte{'a', 'b', 'c'}}, lock.Load())
+
+	const writersNumber = 4
+	const updatesNumber = 1000
+
+	stop := make(chan struct{})
+	readers := sync.WaitGroup{}
+	readers.Add(4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				value := lock.Load()
+				assert.Equal(t, value.x, value.y)
+				assert.Equal(t, value.x*2, value.z)
+			}
+		}()
+	}
+
+	writers := sync.WaitGroup{}
+	writers.Add(writersNumber)
+	for i := 0; i < writersNumber; i++ {
+		go func() {
+			defer writers.Done()
+			for j := 0; j < updatesNumber; j++ {
+				lock.Update(func(value point) point {
+					value.x++
+					value.y++
+					value.z += 2
+					return value
+				})
+			}
+		}()
+	}
+
+	writers.Wait()
+	close(stop)
+	readers.Wait()
+
+	value := lock.Load()
+	assert.Equal(t, int64(writersNumber*updatesNumber), value.x)
+	assert.Equal(t, [3]byte{'a', 'b', 'c'}, value.tag)
+
+	lock.Store(point{x: 1, y: 1, z: 2})
+	assert.Equal(t, point{x: 1, y: 1, z: 2}, lock.Load())
+}
+
+func TestSeqLockWithPointers(t *testing.T) {
+	assert.Panics(t, func() { NewSeqLock("string") })
+	assert.Panics(t, func() { NewSeqLock(struct{ values []int }{}) })
+	assert.Panics(t, func() { NewSeqLock([2]*int{}) })
+	assert.NotPanics(t, func() { NewSeqLock([0]*int{}) })
+	assert.NotPanics(t, func() { NewSeqLock(struct{}{}) })
+}